@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// fileConfig lists the settings accepted in the file passed with --config. Keys
+// are named after the command line flags they correspond to.
+type fileConfig struct {
+	// GitHub connection
+	Host                string   `mapstructure:"gh-host"`
+	RESTURL             string   `mapstructure:"rest-url"`
+	GraphQLURL          string   `mapstructure:"graphql-url"`
+	UploadURL           string   `mapstructure:"upload-url"`
+	RawURL              string   `mapstructure:"raw-url"`
+	CABundleFile        string   `mapstructure:"ca-bundle-file"`
+	HTTPSProxy          string   `mapstructure:"https-proxy"`
+	NoProxy             []string `mapstructure:"no-proxy"`
+	ClientCertFile      string   `mapstructure:"client-cert-file"`
+	ClientKeyFile       string   `mapstructure:"client-key-file"`
+	PersonalAccessToken string   `mapstructure:"personal-access-token"`
+	TokenCacheFile      string   `mapstructure:"token-cache-file"`
+	AppID               int64    `mapstructure:"app-id"`
+	AppPrivateKeyFile   string   `mapstructure:"app-private-key-file"`
+	AppInstallationID   int64    `mapstructure:"app-installation-id"`
+
+	// Tools
+	Toolsets             []string          `mapstructure:"toolsets"`
+	Tools                []string          `mapstructure:"tools"`
+	ExcludeTools         []string          `mapstructure:"exclude-tools"`
+	AllowedRepos         []string          `mapstructure:"allowed-repos"`
+	Lockdown             bool              `mapstructure:"lockdown"`
+	LockdownTrustedUsers []string          `mapstructure:"lockdown-trusted-users"`
+	Sanitize             []string          `mapstructure:"sanitize"`
+	ConfirmTools         []string          `mapstructure:"confirm-tools"`
+	ConfirmDestructive   bool              `mapstructure:"confirm-destructive"`
+	DynamicToolsets      bool              `mapstructure:"dynamic-toolsets"`
+	ReadOnly             bool              `mapstructure:"read-only"`
+	DryRun               bool              `mapstructure:"dry-run"`
+	ContentWindowSize    int               `mapstructure:"content-window-size"`
+	Translations         map[string]string `mapstructure:"translations"`
+
+	// Logging
+	LogFile              string   `mapstructure:"log-file"`
+	EnableCommandLogging bool     `mapstructure:"enable-command-logging"`
+	ExportTranslations   bool     `mapstructure:"export-translations"`
+	RedactPatterns       []string `mapstructure:"redact-pattern"`
+
+	// Observability
+	OTLPEndpoint   string   `mapstructure:"otlp-endpoint"`
+	OTLPHeaders    []string `mapstructure:"otlp-headers"`
+	MetricsAddress string   `mapstructure:"metrics-address"`
+	AuditLog       string   `mapstructure:"audit-log"`
+
+	// Lifecycle and limits, durations such as "30s"
+	ShutdownGracePeriod   string   `mapstructure:"shutdown-grace-period"`
+	ToolTimeout           string   `mapstructure:"tool-timeout"`
+	ToolTimeouts          []string `mapstructure:"tool-timeouts"`
+	MaxConcurrentRequests int      `mapstructure:"max-concurrent-requests"`
+
+	// Subcommands
+	ListenAddress string   `mapstructure:"listen-address"`
+	ClientID      string   `mapstructure:"client-id"`
+	Scopes        []string `mapstructure:"scopes"`
+}
+
+// configFileKeys maps the keys of the configuration file to the viper keys of
+// the settings, where they differ.
+var configFileKeys = map[string]string{
+	"gh-host":               "host",
+	"dynamic-toolsets":      "dynamic_toolsets",
+	"personal-access-token": "personal_access_token",
+}
+
+// loadConfigFile reads the YAML or JSON configuration file at path into target,
+// where it takes precedence over defaults but not over environment variables or
+// flags. It replaces the settings of a previously loaded file, so that it can be
+// called again on reload. Unknown keys are rejected, so that typos don't go
+// unnoticed.
+func loadConfigFile(target *viper.Viper, path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("config file %s must be YAML (.yaml, .yml) or JSON (.json)", path)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	settings := v.AllSettings()
+
+	var cfg fileConfig
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused: true,
+		Result:      &cfg,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(settings); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	for fileKey, viperKey := range configFileKeys {
+		if value, ok := settings[fileKey]; ok {
+			delete(settings, fileKey)
+			settings[viperKey] = value
+		}
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	target.SetConfigType("json")
+	return target.ReadConfig(bytes.NewReader(data))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_loadConfigFile(t *testing.T) {
+	t.Run("YAML", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+gh-host: https://github.example.com
+toolsets: [repos, issues]
+dynamic-toolsets: true
+read-only: true
+content-window-size: 2000
+shutdown-grace-period: 1m
+tool-timeouts:
+  - get_job_logs=10m
+no-proxy:
+  - localhost
+redact-pattern:
+  - 'ACME-[0-9A-F]{32}'
+  - 'a{1,3},b'
+translations:
+  TOOL_GET_ME_DESCRIPTION: Who am I?
+`)
+		v := viper.New()
+		require.NoError(t, loadConfigFile(v, path))
+
+		assert.Equal(t, "https://github.example.com", v.GetString("host"))
+		assert.Equal(t, []string{"repos", "issues"}, v.GetStringSlice("toolsets"))
+		assert.True(t, v.GetBool("dynamic_toolsets"))
+		assert.True(t, v.GetBool("read-only"))
+		assert.Equal(t, 2000, v.GetInt("content-window-size"))
+		assert.Equal(t, time.Minute, v.GetDuration("shutdown-grace-period"))
+		assert.Equal(t, []string{"get_job_logs=10m"}, v.GetStringSlice("tool-timeouts"))
+		assert.Equal(t, []string{"localhost"}, v.GetStringSlice("no-proxy"))
+		assert.Equal(t, []string{"ACME-[0-9A-F]{32}", "a{1,3},b"}, v.GetStringSlice("redact-pattern"))
+		assert.Equal(t, "Who am I?", v.GetStringMapString("translations")["tool_get_me_description"])
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		path := writeConfigFile(t, "config.json", `{"content-window-size": 3000, "app-id": 1234}`)
+		v := viper.New()
+		require.NoError(t, loadConfigFile(v, path))
+
+		assert.Equal(t, 3000, v.GetInt("content-window-size"))
+		assert.Equal(t, int64(1234), v.GetInt64("app-id"))
+	})
+
+	t.Run("flags and environment take precedence", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "read-only: true\nlog-file: config.log\ncontent-window-size: 2000\n")
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.Bool("read-only", false, "")
+		flags.String("log-file", "", "")
+		flags.Int("content-window-size", 5000, "")
+		require.NoError(t, flags.Parse([]string{"--read-only=false"}))
+
+		t.Setenv("GITHUB_LOG_FILE", "env.log")
+		v := viper.New()
+		v.SetEnvPrefix("github")
+		v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+		v.AutomaticEnv()
+		require.NoError(t, v.BindPFlags(flags))
+		require.NoError(t, loadConfigFile(v, path))
+
+		assert.False(t, v.GetBool("read-only"), "flag must override the config file")
+		assert.Equal(t, "env.log", v.GetString("log-file"), "environment must override the config file")
+		assert.Equal(t, 2000, v.GetInt("content-window-size"), "config file must override flag defaults")
+	})
+
+	t.Run("reloading replaces the previous settings", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "read-only: true\ntoolsets: [repos]\n")
+		v := viper.New()
+		require.NoError(t, loadConfigFile(v, path))
+		require.True(t, v.GetBool("read-only"))
+
+		require.NoError(t, os.WriteFile(path, []byte("toolsets: [issues]\n"), 0600))
+		require.NoError(t, loadConfigFile(v, path))
+
+		assert.False(t, v.GetBool("read-only"))
+		assert.Equal(t, []string{"issues"}, v.GetStringSlice("toolsets"))
+	})
+
+	t.Run("unknown keys are rejected", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "read-only: true\nreadonly: true\n")
+		err := loadConfigFile(viper.New(), path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "readonly")
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "content-window-size: large\n")
+		err := loadConfigFile(viper.New(), path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "content-window-size")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		path := writeConfigFile(t, "config.toml", "read-only = true\n")
+		assert.EqualError(t, loadConfigFile(viper.New(), path), "config file "+path+" must be YAML (.yaml, .yml) or JSON (.json)")
+	})
+}
@@ -24,7 +24,15 @@ var generateDocsCmd = &cobra.Command{
 	Short: "Generate documentation for tools and toolsets",
 	Long:  `Generate the automated sections of README.md and docs/remote-server.md with current tool and toolset information.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		return generateAllDocs()
+		enabledTools, excludedTools, err := getToolPatterns()
+		if err != nil {
+			return err
+		}
+		toolFilter, err := toolsets.NewToolNameFilter(enabledTools, excludedTools)
+		if err != nil {
+			return err
+		}
+		return generateAllDocs(toolFilter)
 	},
 }
 
@@ -47,8 +55,9 @@ func mockGetRawClient(_ context.Context) (*raw.Client, error) {
 	return nil, nil
 }
 
-func generateAllDocs() error {
-	if err := generateReadmeDocs("README.md"); err != nil {
+// generateAllDocs documents the tools toolFilter allows.
+func generateAllDocs(toolFilter toolsets.ToolFilter) error {
+	if err := generateReadmeDocs("README.md", toolFilter); err != nil {
 		return fmt.Errorf("failed to generate README docs: %w", err)
 	}
 
@@ -59,12 +68,13 @@ func generateAllDocs() error {
 	return nil
 }
 
-func generateReadmeDocs(readmePath string) error {
+func generateReadmeDocs(readmePath string, toolFilter toolsets.ToolFilter) error {
 	// Create translation helper
 	t, _ := translations.TranslationHelper()
 
 	// Create toolset group with mock clients
-	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, t, 5000)
+	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, nil, t, 5000)
+	tsg.AddToolFilter(toolFilter)
 
 	// Generate toolsets documentation
 	toolsetsDoc := generateToolsetsDoc(tsg)
@@ -302,7 +312,7 @@ func generateRemoteToolsetsDoc() string {
 	t, _ := translations.TranslationHelper()
 
 	// Create toolset group with mock clients
-	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, t, 5000)
+	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, nil, t, 5000)
 
 	// Generate table header
 	buf.WriteString("| Name           | Description                                      | API URL                                               | 1-Click Install (VS Code)                                                                                                                                                                                                 | Read-only Link                                                                                                 | 1-Click Read-only Install (VS Code)                                                                                                                                                                                                 |\n")
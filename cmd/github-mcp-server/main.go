@@ -3,10 +3,14 @@ package main
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/github/github-mcp-server/pkg/auth"
 	"github.com/github/github-mcp-server/pkg/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -24,6 +28,12 @@ var (
 		Short:   "GitHub MCP Server",
 		Long:    `A GitHub MCP server that handles various tools and resources.`,
 		Version: fmt.Sprintf("Version: %s\nCommit: %s\nBuild Date: %s", version, commit, date),
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			if path := viper.GetString("config"); path != "" {
+				return loadConfigFile(viper.GetViper(), path)
+			}
+			return nil
+		},
 	}
 
 	stdioCmd = &cobra.Command{
@@ -31,38 +41,177 @@ var (
 		Short: "Start stdio server",
 		Long:  `Start a server that communicates via standard input/output streams using JSON-RPC messages.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
+			stdioServerConfig, err := getStdioServerConfig()
+			if err != nil {
+				return err
+			}
+			stdioServerConfig.Reload = reloadStdioServerConfig
+			return ghmcp.RunStdioServer(stdioServerConfig)
+		},
+	}
+
+	httpCmd = &cobra.Command{
+		Use:   "http",
+		Short: "Start streamable HTTP server",
+		Long:  `Start a server that communicates via the MCP streamable HTTP transport. Each request must authenticate with a GitHub token in its Authorization header.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			enabledToolsets, err := getEnabledToolsets()
+			if err != nil {
+				return err
+			}
+
+			enabledTools, excludedTools, err := getToolPatterns()
+			if err != nil {
+				return err
+			}
+
+			allowedRepositories, err := getAllowedRepositories()
+			if err != nil {
+				return err
+			}
+
+			lockdownTrustedUsers, err := getLockdownTrustedUsers()
+			if err != nil {
+				return err
+			}
+
+			sanitizeRules, err := getSanitizeRules()
+			if err != nil {
+				return err
+			}
+
+			confirmTools, err := getConfirmTools()
+			if err != nil {
+				return err
+			}
+
+			networkConfig, err := getNetworkConfig()
+			if err != nil {
+				return err
+			}
+
+			tracingConfig, err := getTracingConfig()
+			if err != nil {
+				return err
+			}
+
+			toolTimeouts, err := getToolTimeouts()
+			if err != nil {
+				return err
+			}
+
+			httpServerConfig := ghmcp.HTTPServerConfig{
+				Version:                 version,
+				Host:                    viper.GetString("host"),
+				APIURLs:                 getAPIURLs(),
+				Network:                 networkConfig,
+				EnabledToolsets:         enabledToolsets,
+				EnabledTools:            enabledTools,
+				ExcludedTools:           excludedTools,
+				AllowedRepositories:     allowedRepositories,
+				Lockdown:                viper.GetBool("lockdown"),
+				LockdownTrustedUsers:    lockdownTrustedUsers,
+				SanitizeRules:           sanitizeRules,
+				ConfirmTools:            confirmTools,
+				ConfirmDestructiveTools: viper.GetBool("confirm-destructive"),
+				DynamicToolsets:         viper.GetBool("dynamic_toolsets"),
+				ReadOnly:                viper.GetBool("read-only"),
+				DryRun:                  viper.GetBool("dry-run"),
+				ExportTranslations:      viper.GetBool("export-translations"),
+				TranslationOverrides:    viper.GetStringMapString("translations"),
+				LogFilePath:             viper.GetString("log-file"),
+				ContentWindowSize:       viper.GetInt("content-window-size"),
+				Tracing:                 tracingConfig,
+				MetricsAddress:          viper.GetString("metrics-address"),
+				AuditLogPath:            viper.GetString("audit-log"),
+				RedactPatterns:          getRedactPatterns(),
+				ShutdownGracePeriod:     viper.GetDuration("shutdown-grace-period"),
+				ToolTimeout:             viper.GetDuration("tool-timeout"),
+				ToolTimeouts:            toolTimeouts,
+				MaxConcurrentRequests:   viper.GetInt("max-concurrent-requests"),
+				ListenAddress:           viper.GetString("listen-address"),
+			}
+			return ghmcp.RunHTTPServer(httpServerConfig)
+		},
+	}
+
+	loginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Log in to GitHub",
+		Long:  `Obtain a token through the OAuth device flow and store it in the token cache. The stdio server uses the cached token when GITHUB_PERSONAL_ACCESS_TOKEN is not set.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			var scopes []string
+			if err := viper.UnmarshalKey("scopes", &scopes); err != nil {
+				return fmt.Errorf("failed to unmarshal scopes: %w", err)
+			}
+
+			networkConfig, err := getNetworkConfig()
+			if err != nil {
+				return err
+			}
+
+			return ghmcp.RunLogin(ghmcp.LoginConfig{
+				Host:           viper.GetString("host"),
+				Network:        networkConfig,
+				ClientID:       viper.GetString("client-id"),
+				Scopes:         scopes,
+				TokenCachePath: viper.GetString("token-cache-file"),
+			})
+		},
+	}
+
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the host, token and permissions",
+		Long:  `Report the API endpoints of the configured host, validate the token, show its scopes, expiry and rate limit, or the permissions of a GitHub App installation, and make one request per enabled toolset to confirm the token can use it. Exits with an error if a problem is found.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// The GitHub App flags of doctor replace those of stdio
+			bindAppFlags(cmd)
 			token := viper.GetString("personal_access_token")
-			if token == "" {
-				return errors.New("GITHUB_PERSONAL_ACCESS_TOKEN not set")
+			appID := viper.GetInt64("app-id")
+			if token == "" && appID == 0 {
+				// Fall back to a token stored by `github-mcp-server login`
+				cachedToken, err := ghmcp.CachedToken(viper.GetString("host"), viper.GetString("token-cache-file"))
+				if err != nil && !errors.Is(err, auth.ErrNoCachedToken) {
+					return fmt.Errorf("failed to load cached token: %w", err)
+				}
+				token = cachedToken
+			}
+			if appID != 0 && (viper.GetString("app-private-key-file") == "" || viper.GetInt64("app-installation-id") == 0) {
+				return errors.New("GitHub App authentication requires --app-private-key-file and --app-installation-id")
 			}
 
-			// If you're wondering why we're not using viper.GetStringSlice("toolsets"),
-			// it's because viper doesn't handle comma-separated values correctly for env
-			// vars when using GetStringSlice.
-			// https://github.com/spf13/viper/issues/380
-			var enabledToolsets []string
-			if err := viper.UnmarshalKey("toolsets", &enabledToolsets); err != nil {
-				return fmt.Errorf("failed to unmarshal toolsets: %w", err)
+			enabledToolsets, err := getEnabledToolsets()
+			if err != nil {
+				return err
 			}
 
-			// No passed toolsets configuration means we enable the default toolset
-			if len(enabledToolsets) == 0 {
-				enabledToolsets = []string{github.ToolsetMetadataDefault.ID}
+			networkConfig, err := getNetworkConfig()
+			if err != nil {
+				return err
 			}
 
-			stdioServerConfig := ghmcp.StdioServerConfig{
-				Version:              version,
-				Host:                 viper.GetString("host"),
-				Token:                token,
-				EnabledToolsets:      enabledToolsets,
-				DynamicToolsets:      viper.GetBool("dynamic_toolsets"),
-				ReadOnly:             viper.GetBool("read-only"),
-				ExportTranslations:   viper.GetBool("export-translations"),
-				EnableCommandLogging: viper.GetBool("enable-command-logging"),
-				LogFilePath:          viper.GetString("log-file"),
-				ContentWindowSize:    viper.GetInt("content-window-size"),
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			repository, _ := cmd.Flags().GetString("repo")
+			err = ghmcp.RunDoctor(ghmcp.DoctorConfig{
+				Version:           version,
+				Host:              viper.GetString("host"),
+				APIURLs:           getAPIURLs(),
+				Network:           networkConfig,
+				Token:             token,
+				AppID:             appID,
+				AppPrivateKeyPath: viper.GetString("app-private-key-file"),
+				AppInstallationID: viper.GetInt64("app-installation-id"),
+				EnabledToolsets:   enabledToolsets,
+				Repository:        repository,
+				JSON:              jsonOutput,
+			})
+			if err != nil {
+				// The report explains the problems, only the exit status is left to set
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
 			}
-			return ghmcp.RunStdioServer(stdioServerConfig)
+			return err
 		},
 	}
 )
@@ -74,27 +223,110 @@ func init() {
 	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
 
 	// Add global flags that will be shared by all commands
+	rootCmd.PersistentFlags().String("config", "", "Path to a YAML or JSON configuration file")
 	rootCmd.PersistentFlags().StringSlice("toolsets", nil, github.GenerateToolsetsHelp())
+	rootCmd.PersistentFlags().StringSlice("tools", nil, "Comma-separated list of tools to offer from the enabled toolsets, supports globs such as get_* (default is all tools)")
+	rootCmd.PersistentFlags().StringSlice("exclude-tools", nil, "Comma-separated list of tools never to offer, supports globs such as delete_*")
+	rootCmd.PersistentFlags().StringSlice("allowed-repos", nil, "Comma-separated list of owners and owner/repo patterns to restrict all tools to, e.g. octo-org/app,octo-org/svc-* (default is everything the token can access)")
+	rootCmd.PersistentFlags().Bool("lockdown", false, "Withhold issue, pull request and discussion content written by users without push access to the repository")
+	rootCmd.PersistentFlags().StringSlice("lockdown-trusted-users", nil, "Comma-separated list of users whose content is shown in lockdown mode regardless of their access")
+	rootCmd.PersistentFlags().StringSlice("sanitize", []string{"none"}, "Comma-separated list of rules removing hidden content from user-authored text in tool results: invisible-characters, html-comments, image-alt, details, all or none")
+	rootCmd.PersistentFlags().StringSlice("confirm-tools", nil, "Comma-separated list of write tools that ask the user for confirmation before they run, supports globs such as delete_*")
+	rootCmd.PersistentFlags().Bool("confirm-destructive", false, "Ask the user for confirmation before running any destructive tool, e.g. delete_file or merge_pull_request")
 	rootCmd.PersistentFlags().Bool("dynamic-toolsets", false, "Enable dynamic toolsets")
 	rootCmd.PersistentFlags().Bool("read-only", false, "Restrict the server to read-only operations")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Make write tools describe the changes they would make instead of making them")
 	rootCmd.PersistentFlags().String("log-file", "", "Path to log file")
 	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled, the server will log all command requests and responses to the log file")
+	rootCmd.PersistentFlags().StringArray("redact-pattern", nil, "Regular expression matching secrets to redact from logs and the audit log, in addition to the built-in detectors (can be repeated)")
 	rootCmd.PersistentFlags().Bool("export-translations", false, "Save translations to a JSON file")
 	rootCmd.PersistentFlags().String("gh-host", "", "Specify the GitHub hostname (for GitHub Enterprise etc.)")
+	rootCmd.PersistentFlags().String("rest-url", "", "Override the REST API base URL, e.g. http://localhost:8080/api/v3/")
+	rootCmd.PersistentFlags().String("graphql-url", "", "Override the GraphQL API URL")
+	rootCmd.PersistentFlags().String("upload-url", "", "Override the upload API base URL")
+	rootCmd.PersistentFlags().String("raw-url", "", "Override the raw content base URL")
+	rootCmd.PersistentFlags().String("ca-bundle-file", "", "Path to a PEM file with CA certificates to trust in addition to the system roots")
+	rootCmd.PersistentFlags().String("https-proxy", "", "Proxy URL to connect to GitHub through (default is taken from the HTTPS_PROXY environment variable)")
+	rootCmd.PersistentFlags().StringSlice("no-proxy", nil, "Comma-separated list of hosts, IP addresses or CIDR ranges to connect to without --https-proxy")
+	rootCmd.PersistentFlags().String("client-cert-file", "", "Path to a PEM encoded client certificate for mutual TLS")
+	rootCmd.PersistentFlags().String("client-key-file", "", "Path to the PEM encoded private key of the client certificate")
 	rootCmd.PersistentFlags().Int("content-window-size", 5000, "Specify the content window size")
+	rootCmd.PersistentFlags().String("token-cache-file", "", "Path to the token cache written by the login command (default is in the user config directory)")
+	rootCmd.PersistentFlags().String("otlp-endpoint", "", "OTLP/HTTP endpoint to export traces to, e.g. http://localhost:4318 (default is taken from the OTEL_EXPORTER_OTLP_ENDPOINT environment variable)")
+	rootCmd.PersistentFlags().StringSlice("otlp-headers", nil, "Comma-separated list of key=value headers to send with exported traces (default is taken from the OTEL_EXPORTER_OTLP_HEADERS environment variable)")
+	rootCmd.PersistentFlags().String("audit-log", "", "Path to a file to append a JSON-lines record of every write tool call to")
+	rootCmd.PersistentFlags().String("metrics-address", "", "Address to serve Prometheus metrics on at /metrics, e.g. localhost:9464 (disabled by default)")
+	rootCmd.PersistentFlags().Duration("shutdown-grace-period", 30*time.Second, "How long tool calls in flight get to finish on shutdown before they are cancelled")
+	rootCmd.PersistentFlags().Duration("tool-timeout", 5*time.Minute, "How long a tool call may take before it is cancelled, 0 for no limit")
+	rootCmd.PersistentFlags().StringSlice("tool-timeouts", nil, "Comma-separated list of tool=duration timeouts overriding --tool-timeout for specific tools, e.g. get_job_logs=10m")
+	rootCmd.PersistentFlags().Int("max-concurrent-requests", 10, "Maximum number of GitHub API requests in flight across all tool calls, 0 for no limit")
 
 	// Bind flag to viper
+	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
+	_ = viper.BindPFlag("tools", rootCmd.PersistentFlags().Lookup("tools"))
+	_ = viper.BindPFlag("exclude-tools", rootCmd.PersistentFlags().Lookup("exclude-tools"))
+	_ = viper.BindPFlag("allowed-repos", rootCmd.PersistentFlags().Lookup("allowed-repos"))
+	_ = viper.BindPFlag("lockdown", rootCmd.PersistentFlags().Lookup("lockdown"))
+	_ = viper.BindPFlag("lockdown-trusted-users", rootCmd.PersistentFlags().Lookup("lockdown-trusted-users"))
+	_ = viper.BindPFlag("sanitize", rootCmd.PersistentFlags().Lookup("sanitize"))
+	_ = viper.BindPFlag("confirm-tools", rootCmd.PersistentFlags().Lookup("confirm-tools"))
+	_ = viper.BindPFlag("confirm-destructive", rootCmd.PersistentFlags().Lookup("confirm-destructive"))
 	_ = viper.BindPFlag("dynamic_toolsets", rootCmd.PersistentFlags().Lookup("dynamic-toolsets"))
 	_ = viper.BindPFlag("read-only", rootCmd.PersistentFlags().Lookup("read-only"))
+	_ = viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
 	_ = viper.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
 	_ = viper.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
+	_ = viper.BindPFlag("redact-pattern", rootCmd.PersistentFlags().Lookup("redact-pattern"))
 	_ = viper.BindPFlag("export-translations", rootCmd.PersistentFlags().Lookup("export-translations"))
 	_ = viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("gh-host"))
+	_ = viper.BindPFlag("rest-url", rootCmd.PersistentFlags().Lookup("rest-url"))
+	_ = viper.BindPFlag("graphql-url", rootCmd.PersistentFlags().Lookup("graphql-url"))
+	_ = viper.BindPFlag("upload-url", rootCmd.PersistentFlags().Lookup("upload-url"))
+	_ = viper.BindPFlag("raw-url", rootCmd.PersistentFlags().Lookup("raw-url"))
+	_ = viper.BindPFlag("ca-bundle-file", rootCmd.PersistentFlags().Lookup("ca-bundle-file"))
+	_ = viper.BindPFlag("https-proxy", rootCmd.PersistentFlags().Lookup("https-proxy"))
+	_ = viper.BindPFlag("no-proxy", rootCmd.PersistentFlags().Lookup("no-proxy"))
+	_ = viper.BindPFlag("client-cert-file", rootCmd.PersistentFlags().Lookup("client-cert-file"))
+	_ = viper.BindPFlag("client-key-file", rootCmd.PersistentFlags().Lookup("client-key-file"))
 	_ = viper.BindPFlag("content-window-size", rootCmd.PersistentFlags().Lookup("content-window-size"))
+	_ = viper.BindPFlag("token-cache-file", rootCmd.PersistentFlags().Lookup("token-cache-file"))
+	_ = viper.BindPFlag("otlp-endpoint", rootCmd.PersistentFlags().Lookup("otlp-endpoint"))
+	_ = viper.BindPFlag("otlp-headers", rootCmd.PersistentFlags().Lookup("otlp-headers"))
+	_ = viper.BindPFlag("metrics-address", rootCmd.PersistentFlags().Lookup("metrics-address"))
+	_ = viper.BindPFlag("audit-log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	_ = viper.BindPFlag("shutdown-grace-period", rootCmd.PersistentFlags().Lookup("shutdown-grace-period"))
+	_ = viper.BindPFlag("tool-timeout", rootCmd.PersistentFlags().Lookup("tool-timeout"))
+	_ = viper.BindPFlag("tool-timeouts", rootCmd.PersistentFlags().Lookup("tool-timeouts"))
+	_ = viper.BindPFlag("max-concurrent-requests", rootCmd.PersistentFlags().Lookup("max-concurrent-requests"))
+	// Also honor the standard OpenTelemetry environment variables
+	_ = viper.BindEnv("otlp-endpoint", "GITHUB_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	_ = viper.BindEnv("otlp-headers", "GITHUB_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_HEADERS")
+
+	// Add stdio specific flags
+	addAppFlags(stdioCmd)
+	bindAppFlags(stdioCmd)
+
+	// Add http specific flags
+	httpCmd.Flags().String("listen-address", "localhost:8082", "Address for the HTTP server to listen on")
+	_ = viper.BindPFlag("listen-address", httpCmd.Flags().Lookup("listen-address"))
+
+	// Add login specific flags
+	loginCmd.Flags().String("client-id", "", "Client ID of the OAuth app to authorize with the device flow")
+	loginCmd.Flags().StringSlice("scopes", []string{"repo", "read:org", "gist", "notifications", "workflow", "project"}, "Comma-separated list of OAuth scopes to request")
+	_ = viper.BindPFlag("client-id", loginCmd.Flags().Lookup("client-id"))
+	_ = viper.BindPFlag("scopes", loginCmd.Flags().Lookup("scopes"))
+
+	// Add doctor specific flags
+	doctorCmd.Flags().Bool("json", false, "Write the report as JSON")
+	doctorCmd.Flags().String("repo", "", "Repository to probe the toolsets acting on repositories with, as owner/repo (default is the repository pushed to last)")
+	addAppFlags(doctorCmd)
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(httpCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 func initConfig() {
@@ -105,6 +337,309 @@ func initConfig() {
 
 }
 
+// getEnabledToolsets returns the configured toolsets, falling back to the default toolset.
+func getEnabledToolsets() ([]string, error) {
+	// If you're wondering why we're not using viper.GetStringSlice("toolsets"),
+	// it's because viper doesn't handle comma-separated values correctly for env
+	// vars when using GetStringSlice.
+	// https://github.com/spf13/viper/issues/380
+	var enabledToolsets []string
+	if err := viper.UnmarshalKey("toolsets", &enabledToolsets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal toolsets: %w", err)
+	}
+
+	// No passed toolsets configuration means we enable the default toolset
+	if len(enabledToolsets) == 0 {
+		enabledToolsets = []string{github.ToolsetMetadataDefault.ID}
+	}
+	return enabledToolsets, nil
+}
+
+// getToolPatterns returns the configured patterns of the tools to offer and of
+// the tools to exclude.
+func getToolPatterns() ([]string, []string, error) {
+	var enabledTools, excludedTools []string
+	if err := viper.UnmarshalKey("tools", &enabledTools); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal tools: %w", err)
+	}
+	if err := viper.UnmarshalKey("exclude-tools", &excludedTools); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal exclude-tools: %w", err)
+	}
+	return enabledTools, excludedTools, nil
+}
+
+// getAllowedRepositories returns the owners and repositories tools are restricted to.
+func getAllowedRepositories() ([]string, error) {
+	var allowedRepositories []string
+	if err := viper.UnmarshalKey("allowed-repos", &allowedRepositories); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allowed-repos: %w", err)
+	}
+	return allowedRepositories, nil
+}
+
+// getLockdownTrustedUsers returns the users trusted in lockdown mode.
+func getLockdownTrustedUsers() ([]string, error) {
+	var trustedUsers []string
+	if err := viper.UnmarshalKey("lockdown-trusted-users", &trustedUsers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lockdown-trusted-users: %w", err)
+	}
+	return trustedUsers, nil
+}
+
+// getSanitizeRules returns the rules sanitizing tool results.
+func getSanitizeRules() ([]string, error) {
+	var rules []string
+	if err := viper.UnmarshalKey("sanitize", &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sanitize: %w", err)
+	}
+	return rules, nil
+}
+
+// getConfirmTools returns the patterns of the tools that ask for confirmation.
+func getConfirmTools() ([]string, error) {
+	var confirmTools []string
+	if err := viper.UnmarshalKey("confirm-tools", &confirmTools); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal confirm-tools: %w", err)
+	}
+	return confirmTools, nil
+}
+
+// getAPIURLs returns the explicitly configured API endpoints.
+func getAPIURLs() ghmcp.APIURLs {
+	return ghmcp.APIURLs{
+		REST:    viper.GetString("rest-url"),
+		GraphQL: viper.GetString("graphql-url"),
+		Upload:  viper.GetString("upload-url"),
+		Raw:     viper.GetString("raw-url"),
+	}
+}
+
+// getNetworkConfig returns the configured network options.
+func getNetworkConfig() (ghmcp.NetworkConfig, error) {
+	var noProxy []string
+	if err := viper.UnmarshalKey("no-proxy", &noProxy); err != nil {
+		return ghmcp.NetworkConfig{}, fmt.Errorf("failed to unmarshal no-proxy: %w", err)
+	}
+	return ghmcp.NetworkConfig{
+		CABundlePath:   viper.GetString("ca-bundle-file"),
+		ProxyURL:       viper.GetString("https-proxy"),
+		NoProxy:        noProxy,
+		ClientCertPath: viper.GetString("client-cert-file"),
+		ClientKeyPath:  viper.GetString("client-key-file"),
+	}, nil
+}
+
+// addAppFlags adds the flags configuring authentication as a GitHub App
+// installation to cmd.
+func addAppFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64("app-id", 0, "GitHub App ID, to authenticate as a GitHub App installation instead of with a personal access token")
+	cmd.Flags().String("app-private-key-file", "", "Path to the PEM encoded private key of the GitHub App")
+	cmd.Flags().Int64("app-installation-id", 0, "ID of the GitHub App installation to authenticate as")
+}
+
+// bindAppFlags binds the GitHub App flags of cmd to their configuration keys.
+// Only one command can be bound at a time.
+func bindAppFlags(cmd *cobra.Command) {
+	_ = viper.BindPFlag("app-id", cmd.Flags().Lookup("app-id"))
+	_ = viper.BindPFlag("app-private-key-file", cmd.Flags().Lookup("app-private-key-file"))
+	_ = viper.BindPFlag("app-installation-id", cmd.Flags().Lookup("app-installation-id"))
+}
+
+// getStdioServerConfig returns the configuration of the stdio server.
+func getStdioServerConfig() (ghmcp.StdioServerConfig, error) {
+	token := viper.GetString("personal_access_token")
+	appID := viper.GetInt64("app-id")
+	if token == "" && appID == 0 {
+		// Fall back to a token stored by `github-mcp-server login`
+		cachedToken, err := ghmcp.CachedToken(viper.GetString("host"), viper.GetString("token-cache-file"))
+		switch {
+		case errors.Is(err, auth.ErrNoCachedToken):
+			return ghmcp.StdioServerConfig{}, errors.New("GITHUB_PERSONAL_ACCESS_TOKEN not set, and no cached token found; set it or run `github-mcp-server login`")
+		case err != nil:
+			return ghmcp.StdioServerConfig{}, fmt.Errorf("failed to load cached token: %w", err)
+		}
+		token = cachedToken
+	}
+	if appID != 0 && (viper.GetString("app-private-key-file") == "" || viper.GetInt64("app-installation-id") == 0) {
+		return ghmcp.StdioServerConfig{}, errors.New("GitHub App authentication requires --app-private-key-file and --app-installation-id")
+	}
+
+	enabledToolsets, err := getEnabledToolsets()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	enabledTools, excludedTools, err := getToolPatterns()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	allowedRepositories, err := getAllowedRepositories()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	lockdownTrustedUsers, err := getLockdownTrustedUsers()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	sanitizeRules, err := getSanitizeRules()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	confirmTools, err := getConfirmTools()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	networkConfig, err := getNetworkConfig()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	tracingConfig, err := getTracingConfig()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	toolTimeouts, err := getToolTimeouts()
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	return ghmcp.StdioServerConfig{
+		Version:                 version,
+		Host:                    viper.GetString("host"),
+		APIURLs:                 getAPIURLs(),
+		Network:                 networkConfig,
+		Token:                   token,
+		AppID:                   appID,
+		AppPrivateKeyPath:       viper.GetString("app-private-key-file"),
+		AppInstallationID:       viper.GetInt64("app-installation-id"),
+		EnabledToolsets:         enabledToolsets,
+		EnabledTools:            enabledTools,
+		ExcludedTools:           excludedTools,
+		AllowedRepositories:     allowedRepositories,
+		Lockdown:                viper.GetBool("lockdown"),
+		LockdownTrustedUsers:    lockdownTrustedUsers,
+		SanitizeRules:           sanitizeRules,
+		ConfirmTools:            confirmTools,
+		ConfirmDestructiveTools: viper.GetBool("confirm-destructive"),
+		DynamicToolsets:         viper.GetBool("dynamic_toolsets"),
+		ReadOnly:                viper.GetBool("read-only"),
+		DryRun:                  viper.GetBool("dry-run"),
+		ExportTranslations:      viper.GetBool("export-translations"),
+		TranslationOverrides:    viper.GetStringMapString("translations"),
+		EnableCommandLogging:    viper.GetBool("enable-command-logging"),
+		LogFilePath:             viper.GetString("log-file"),
+		ContentWindowSize:       viper.GetInt("content-window-size"),
+		Tracing:                 tracingConfig,
+		MetricsAddress:          viper.GetString("metrics-address"),
+		AuditLogPath:            viper.GetString("audit-log"),
+		RedactPatterns:          getRedactPatterns(),
+		ShutdownGracePeriod:     viper.GetDuration("shutdown-grace-period"),
+		ToolTimeout:             viper.GetDuration("tool-timeout"),
+		ToolTimeouts:            toolTimeouts,
+		MaxConcurrentRequests:   viper.GetInt("max-concurrent-requests"),
+	}, nil
+}
+
+// reloadStdioServerConfig re-reads the configuration file, if any, and the
+// cached token, and returns the resulting stdio server configuration.
+func reloadStdioServerConfig() (ghmcp.StdioServerConfig, error) {
+	if path := viper.GetString("config"); path != "" {
+		if err := loadConfigFile(viper.GetViper(), path); err != nil {
+			return ghmcp.StdioServerConfig{}, err
+		}
+	}
+	return getStdioServerConfig()
+}
+
+// getRedactPatterns returns the configured redaction patterns. Patterns may
+// contain commas, so the environment variable holds one pattern per line.
+func getRedactPatterns() []string {
+	if value, ok := viper.Get("redact-pattern").(string); ok {
+		var patterns []string
+		for _, pattern := range strings.Split(value, "\n") {
+			if pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+		return patterns
+	}
+	return viper.GetStringSlice("redact-pattern")
+}
+
+// getTracingConfig returns the configured trace export options.
+func getTracingConfig() (tracing.Config, error) {
+	var headerList []string
+	if err := viper.UnmarshalKey("otlp-headers", &headerList); err != nil {
+		return tracing.Config{}, fmt.Errorf("failed to unmarshal otlp-headers: %w", err)
+	}
+	headers, err := parseOTLPHeaders(headerList)
+	if err != nil {
+		return tracing.Config{}, err
+	}
+	return tracing.Config{
+		Endpoint: viper.GetString("otlp-endpoint"),
+		Headers:  headers,
+	}, nil
+}
+
+// parseOTLPHeaders parses key=value pairs in the format of OTEL_EXPORTER_OTLP_HEADERS,
+// where values are URL encoded.
+func parseOTLPHeaders(pairs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid OTLP header %q, expected key=value", pair)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", key, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
+
+// getToolTimeouts returns the configured per-tool timeouts.
+func getToolTimeouts() (map[string]time.Duration, error) {
+	var pairs []string
+	if err := viper.UnmarshalKey("tool-timeouts", &pairs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tool-timeouts: %w", err)
+	}
+	return parseToolTimeouts(pairs)
+}
+
+// parseToolTimeouts parses tool=duration pairs, e.g. get_job_logs=10m.
+func parseToolTimeouts(pairs []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(pairs))
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		tool, value, ok := strings.Cut(pair, "=")
+		tool = strings.TrimSpace(tool)
+		if !ok || tool == "" {
+			return nil, fmt.Errorf("invalid tool timeout %q, expected tool=duration", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid timeout %q for %s, expected a duration such as 10m", strings.TrimSpace(value), tool)
+		}
+		timeouts[tool] = timeout
+	}
+	return timeouts, nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -0,0 +1,139 @@
+// Package audit writes a durable JSON-lines trail of the changes tools make on
+// the user's behalf.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+)
+
+const (
+	// OutcomeSuccess and OutcomeError are the outcomes of an audited tool call.
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	// maxValueLength bounds the length of string arguments in records, so that
+	// file contents and long bodies don't bloat the log.
+	maxValueLength = 1024
+
+	redacted = "[REDACTED]"
+)
+
+// sensitiveArgument matches the names of arguments whose values are never logged.
+var sensitiveArgument = regexp.MustCompile(`(?i)token|secret|password|passphrase|private_?key|credential`)
+
+// Client identifies the MCP client, as reported when the session was initialized.
+type Client struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Record is a single line of the audit log.
+type Record struct {
+	Time       time.Time      `json:"time"`
+	SessionID  string         `json:"session_id,omitempty"`
+	Client     *Client        `json:"client,omitempty"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Repository string         `json:"repository,omitempty"`
+	DryRun     bool           `json:"dry_run,omitempty"`
+	Outcome    string         `json:"outcome"`
+	Error      string         `json:"error,omitempty"`
+	ResultURL  string         `json:"result_url,omitempty"`
+	ResultID   string         `json:"result_id,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+// Log appends records to a file, one JSON object per line. Every record is
+// synced to disk before Write returns, so that the trail survives crashes.
+// It is safe for concurrent use.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open opens the audit log at path for appending, creating it if needed. New
+// files are only readable by the current user.
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 - path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{file: file}, nil
+}
+
+// Write appends record to the log.
+func (l *Log) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// RedactArguments returns a copy of the arguments of a tool call that is safe to
+// log: values of arguments named like credentials are replaced, secrets found by
+// redactor are removed from strings, and long strings, such as file contents,
+// are truncated.
+func RedactArguments(arguments map[string]any, redactor *mcplog.Redactor) map[string]any {
+	if arguments == nil {
+		return nil
+	}
+	return redactValue("", arguments, redactor).(map[string]any)
+}
+
+func redactValue(name string, value any, redactor *mcplog.Redactor) any {
+	if name != "" && sensitiveArgument.MatchString(name) {
+		return redacted
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, value := range v {
+			result[key] = redactValue(key, value, redactor)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, value := range v {
+			result[i] = redactValue("", value, redactor)
+		}
+		return result
+	case string:
+		v = redactor.Redact(v)
+		if len(v) > maxValueLength {
+			cut := maxValueLength
+			for cut > 0 && !utf8.RuneStart(v[cut]) {
+				cut--
+			}
+			return fmt.Sprintf("%s... [truncated, %d bytes]", v[:cut], len(v))
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Log(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"tool\":\"existing\"}\n"), 0600))
+
+	log, err := Open(path)
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, log.Write(Record{
+		Time:       now,
+		Client:     &Client{Name: "vscode", Version: "1.99"},
+		Tool:       "create_issue",
+		Arguments:  map[string]any{"owner": "octocat", "repo": "hello-world", "title": "Bug"},
+		Repository: "octocat/hello-world",
+		Outcome:    OutcomeSuccess,
+		ResultURL:  "https://github.com/octocat/hello-world/issues/1",
+		ResultID:   "1",
+		DurationMS: 120,
+	}))
+	require.NoError(t, log.Write(Record{Time: now, Tool: "merge_pull_request", Outcome: OutcomeError, Error: "not mergeable"}))
+	require.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 3, "records must be appended to the existing log")
+
+	assert.JSONEq(t, `{
+		"time": "2025-01-02T03:04:05Z",
+		"client": {"name": "vscode", "version": "1.99"},
+		"tool": "create_issue",
+		"arguments": {"owner": "octocat", "repo": "hello-world", "title": "Bug"},
+		"repository": "octocat/hello-world",
+		"outcome": "success",
+		"result_url": "https://github.com/octocat/hello-world/issues/1",
+		"result_id": "1",
+		"duration_ms": 120
+	}`, lines[1])
+
+	var record Record
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &record))
+	assert.Equal(t, OutcomeError, record.Outcome)
+	assert.Equal(t, "not mergeable", record.Error)
+}
+
+func Test_RedactArguments(t *testing.T) {
+	long := strings.Repeat("é", maxValueLength)
+	arguments := map[string]any{
+		"owner":        "octocat",
+		"access_token": "ghp_secret",
+		"files": []any{
+			map[string]any{"path": "README.md", "content": long},
+		},
+		"options": map[string]any{"client_secret": "s3cr3t", "draft": true},
+		"number":  float64(42),
+		"body":    "Reproduce with GITHUB_TOKEN=ghp_" + strings.Repeat("a", 36),
+	}
+
+	redactor, err := mcplog.NewRedactor(nil)
+	require.NoError(t, err)
+	redactedArguments := RedactArguments(arguments, redactor)
+
+	assert.Equal(t, "octocat", redactedArguments["owner"])
+	assert.Equal(t, redacted, redactedArguments["access_token"])
+	assert.Equal(t, float64(42), redactedArguments["number"])
+	assert.Equal(t, "Reproduce with GITHUB_TOKEN=[REDACTED:github_token]", redactedArguments["body"])
+	options := redactedArguments["options"].(map[string]any)
+	assert.Equal(t, redacted, options["client_secret"])
+	assert.Equal(t, true, options["draft"])
+
+	content := redactedArguments["files"].([]any)[0].(map[string]any)["content"].(string)
+	assert.True(t, strings.HasSuffix(content, "... [truncated, 2048 bytes]"), content)
+	assert.LessOrEqual(t, len(content), maxValueLength+len("... [truncated, 2048 bytes]"))
+	assert.True(t, json.Valid([]byte(`"`+strings.Split(content, "...")[0]+`"`)))
+
+	// The arguments of the call itself are left untouched
+	assert.Equal(t, "ghp_secret", arguments["access_token"])
+	assert.Nil(t, RedactArguments(nil, nil))
+}
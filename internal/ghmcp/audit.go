@@ -0,0 +1,165 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/audit"
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxTrackedClients bounds the number of sessions whose client info is kept for
+// the audit log. The oldest sessions are forgotten first.
+const maxTrackedClients = 10000
+
+// clientInfos remembers the client info sent on initialization, for sessions
+// that don't keep it themselves, like those of the streamable HTTP transport.
+type clientInfos struct {
+	mu    sync.Mutex
+	infos map[string]mcp.Implementation
+	order []string
+}
+
+func newClientInfos() *clientInfos {
+	return &clientInfos{infos: map[string]mcp.Implementation{}}
+}
+
+// afterInitialize is an OnAfterInitialize hook recording the client info of the session.
+func (c *clientInfos) afterInitialize(ctx context.Context, _ any, request *mcp.InitializeRequest, _ *mcp.InitializeResult) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	if _, ok := session.(server.SessionWithClientInfo); ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.infos[session.SessionID()]; !ok {
+		c.order = append(c.order, session.SessionID())
+	}
+	c.infos[session.SessionID()] = request.Params.ClientInfo
+	for len(c.order) > maxTrackedClients {
+		delete(c.infos, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// get returns the ID and client of the session in ctx.
+func (c *clientInfos) get(ctx context.Context) (string, *audit.Client) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return "", nil
+	}
+
+	var info mcp.Implementation
+	if withClientInfo, ok := session.(server.SessionWithClientInfo); ok {
+		info = withClientInfo.GetClientInfo()
+	} else {
+		c.mu.Lock()
+		info = c.infos[session.SessionID()]
+		c.mu.Unlock()
+	}
+	if info.Name == "" {
+		return session.SessionID(), nil
+	}
+	return session.SessionID(), &audit.Client{Name: info.Name, Version: info.Version}
+}
+
+// auditMiddleware appends a record of every call of the tools it wraps to the
+// audit log. It is applied to write tools only, see ToolsetGroup.AddWriteToolMiddleware.
+// Secrets found by redactor are removed from the arguments and errors recorded.
+func auditMiddleware(log *audit.Log, clients *clientInfos, redactor *mcplog.Redactor) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			arguments := request.GetArguments()
+			record := audit.Record{
+				Time:       start.UTC(),
+				Tool:       request.Params.Name,
+				Arguments:  audit.RedactArguments(arguments, redactor),
+				Repository: repositoryArgument(arguments),
+				DryRun:     isDryRun(ctx),
+				DurationMS: time.Since(start).Milliseconds(),
+			}
+			record.SessionID, record.Client = clients.get(ctx)
+
+			switch {
+			case err != nil:
+				record.Outcome = audit.OutcomeError
+				record.Error = redactor.Redact(err.Error())
+			case result == nil:
+				record.Outcome = audit.OutcomeSuccess
+			case result.IsError:
+				record.Outcome = audit.OutcomeError
+				record.Error = redactor.Redact(resultText(result))
+			default:
+				record.Outcome = audit.OutcomeSuccess
+				record.ResultURL, record.ResultID = resultReference(result)
+			}
+
+			if err := log.Write(record); err != nil {
+				// The change has been made at this point, so failing the call
+				// would only hide it from the model
+				fmt.Fprintf(os.Stderr, "Failed to audit call of %s: %v\n", request.Params.Name, err)
+			}
+			return result, err
+		}
+	}
+}
+
+// repositoryArgument returns the "owner/repo" a tool call targets, if any.
+func repositoryArgument(arguments map[string]any) string {
+	owner, _ := arguments["owner"].(string)
+	repo, _ := arguments["repo"].(string)
+	if owner == "" || repo == "" {
+		return ""
+	}
+	return owner + "/" + repo
+}
+
+// resultReference returns the URL and ID of the object a tool created or changed,
+// taken from the JSON object the tool returned.
+func resultReference(result *mcp.CallToolResult) (string, string) {
+	text := resultText(result)
+	if text == "" {
+		return "", ""
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return "", ""
+	}
+
+	var url, id string
+	for _, key := range []string{"html_url", "url"} {
+		if value, ok := object[key].(string); ok && value != "" {
+			url = value
+			break
+		}
+	}
+	for _, key := range []string{"number", "id", "sha"} {
+		switch value := object[key].(type) {
+		case json.Number:
+			id = value.String()
+		case string:
+			id = value
+		}
+		if id != "" {
+			break
+		}
+	}
+	return url, id
+}
@@ -0,0 +1,122 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/audit"
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is a session that, like those of the streamable HTTP transport,
+// doesn't keep the client info itself.
+type testSession struct {
+	id string
+}
+
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *testSession) SessionID() string                                   { return s.id }
+
+func Test_auditMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = log.Close() }()
+
+	redactor, err := mcplog.NewRedactor(nil)
+	require.NoError(t, err)
+	clients := newClientInfos()
+	srv := server.NewMCPServer("test", "1.0")
+	ctx := srv.WithContext(context.Background(), &testSession{id: "session-1"})
+
+	initialize := &mcp.InitializeRequest{}
+	initialize.Params.ClientInfo = mcp.Implementation{Name: "vscode", Version: "1.99"}
+	clients.afterInitialize(ctx, 1, initialize, nil)
+
+	handlers := map[string]server.ToolHandlerFunc{
+		"create_issue": func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(`{"id":1234567890123,"number":42,"html_url":"https://github.com/octocat/hello-world/issues/42"}`), nil
+		},
+		"merge_pull_request": func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("failed to merge pull request: 405 Pull Request is not mergeable"), nil
+		},
+		"push_files": func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("connection reset using ghp_" + strings.Repeat("a", 36))
+		},
+	}
+	call := func(name string, arguments map[string]any) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = arguments
+		_, _ = auditMiddleware(log, clients, redactor)(handlers[name])(ctx, request)
+	}
+	call("create_issue", map[string]any{"owner": "octocat", "repo": "hello-world", "title": "Bug", "token": "ghp_secret"})
+	call("merge_pull_request", map[string]any{"owner": "octocat", "repo": "hello-world", "pullNumber": float64(7)})
+	call("push_files", map[string]any{"owner": "octocat", "repo": "hello-world"})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+
+	var records []audit.Record
+	for _, line := range lines {
+		var record audit.Record
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	created := records[0]
+	assert.Equal(t, "create_issue", created.Tool)
+	assert.Equal(t, "session-1", created.SessionID)
+	assert.Equal(t, &audit.Client{Name: "vscode", Version: "1.99"}, created.Client)
+	assert.Equal(t, "octocat/hello-world", created.Repository)
+	assert.Equal(t, "[REDACTED]", created.Arguments["token"])
+	assert.Equal(t, audit.OutcomeSuccess, created.Outcome)
+	assert.Equal(t, "https://github.com/octocat/hello-world/issues/42", created.ResultURL)
+	assert.Equal(t, "42", created.ResultID)
+	assert.False(t, created.Time.IsZero())
+
+	merged := records[1]
+	assert.Equal(t, audit.OutcomeError, merged.Outcome)
+	assert.Equal(t, "failed to merge pull request: 405 Pull Request is not mergeable", merged.Error)
+	assert.Empty(t, merged.ResultURL)
+
+	pushed := records[2]
+	assert.Equal(t, audit.OutcomeError, pushed.Outcome)
+	assert.Equal(t, "connection reset using [REDACTED:github_token]", pushed.Error)
+}
+
+func Test_resultReference(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		expectedURL string
+		expectedID  string
+	}{
+		{name: "minimal response", text: `{"id":"123456","url":"https://gist.github.com/123456"}`, expectedURL: "https://gist.github.com/123456", expectedID: "123456"},
+		{name: "large numeric ID", text: `{"id":1234567890123}`, expectedID: "1234567890123"},
+		{name: "merge result", text: `{"sha":"6dcb09b5b57875f334f61aebed695e2e4193db5e","merged":true}`, expectedID: "6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		{name: "not an object", text: `[{"id":1}]`},
+		{name: "plain text", text: `Workflow run has been queued`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			url, id := resultReference(mcp.NewToolResultText(tc.text))
+			assert.Equal(t, tc.expectedURL, url)
+			assert.Equal(t, tc.expectedID, id)
+		})
+	}
+}
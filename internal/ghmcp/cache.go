@@ -0,0 +1,182 @@
+package ghmcp
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	// defaultResponseCacheSize is the total size of the response bodies kept by
+	// the conditional request cache.
+	defaultResponseCacheSize = 32 << 20
+	// maxCachedResponseSize keeps single large responses, such as logs and
+	// archives, from evicting everything else.
+	maxCachedResponseSize = 1 << 20
+)
+
+// conditionalCacheTransport keeps the most recently used GET responses that carry
+// an ETag or Last-Modified header, and revalidates them with If-None-Match or
+// If-Modified-Since. GitHub answers unchanged resources with 304 Not Modified,
+// which doesn't count against the rate limit, and the cached response is served
+// in its place. Entries are keyed by token so that they are never shared between
+// users, and evicted least recently used first once maxSize is exceeded.
+type conditionalCacheTransport struct {
+	transport http.RoundTripper
+	maxSize   int
+
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cachedResponse struct {
+	key          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func newConditionalCacheTransport(transport http.RoundTripper, maxSize int) *conditionalCacheTransport {
+	return &conditionalCacheTransport{
+		transport: transport,
+		maxSize:   maxSize,
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element),
+	}
+}
+
+func (t *conditionalCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests that are already conditional or partial are the caller's business
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		} else {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+		return cached.response(req, resp), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+	if resp.ContentLength > maxCachedResponseSize {
+		return resp, nil
+	}
+
+	// Read at most one byte more than we're willing to cache, so that bodies of
+	// unknown length that turn out too large can be passed on unread.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseSize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedResponseSize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.put(&cachedResponse{
+		key:          key,
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header.Clone(),
+		body:         body,
+	})
+	return resp, nil
+}
+
+// response builds the response served for a revalidated cache entry. Headers of
+// the 304 response, such as the rate limit headers, take precedence over the
+// cached ones.
+func (c *cachedResponse) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := c.header.Clone()
+	for name, values := range notModified.Header {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(c.body)))
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+func (t *conditionalCacheTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	element, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(element)
+	return element.Value.(*cachedResponse)
+}
+
+func (t *conditionalCacheTransport) put(entry *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if element, ok := t.entries[entry.key]; ok {
+		t.size -= len(element.Value.(*cachedResponse).body)
+		t.lru.Remove(element)
+	}
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	t.size += len(entry.body)
+
+	for t.size > t.maxSize {
+		oldest := t.lru.Back()
+		evicted := t.lru.Remove(oldest).(*cachedResponse)
+		delete(t.entries, evicted.key)
+		t.size -= len(evicted.body)
+	}
+}
+
+// cacheKey identifies a response by the token it was requested with, the URL and
+// the requested media type, which changes the representation GitHub returns. The
+// token is hashed so that it isn't kept in memory longer than necessary.
+func cacheKey(req *http.Request) string {
+	token := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(token[:]) + " " + req.Header.Get("Accept") + " " + req.URL.String()
+}
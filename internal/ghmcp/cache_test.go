@@ -0,0 +1,134 @@
+package ghmcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_conditionalCacheTransport(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.Header().Set("X-RateLimit-Remaining", "99")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = io.WriteString(w, "etag body")
+		case "/last-modified":
+			if r.Header.Get("If-Modified-Since") == "Wed, 01 Jan 2025 12:00:00 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 12:00:00 GMT")
+			_, _ = io.WriteString(w, "last modified body")
+		case "/large":
+			w.Header().Set("ETag", `"large"`)
+			_, _ = io.WriteString(w, strings.Repeat("x", maxCachedResponseSize+1))
+		default:
+			_, _ = io.WriteString(w, "uncacheable")
+		}
+	}))
+	defer srv.Close()
+
+	transport := newConditionalCacheTransport(http.DefaultTransport, defaultResponseCacheSize)
+	do := func(t *testing.T, method, path, token string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp, string(body)
+	}
+
+	t.Run("revalidates with the ETag and serves the cached body", func(t *testing.T) {
+		requests = nil
+		_, body := do(t, http.MethodGet, "/etag", "token1")
+		assert.Equal(t, "etag body", body)
+
+		resp, body := do(t, http.MethodGet, "/etag", "token1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "etag body", body)
+		assert.Equal(t, "99", resp.Header.Get("X-RateLimit-Remaining"), "headers of the 304 response take precedence")
+		require.Len(t, requests, 2)
+		assert.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"))
+	})
+
+	t.Run("revalidates with Last-Modified", func(t *testing.T) {
+		requests = nil
+		do(t, http.MethodGet, "/last-modified", "token1")
+		resp, body := do(t, http.MethodGet, "/last-modified", "token1")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "last modified body", body)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "Wed, 01 Jan 2025 12:00:00 GMT", requests[1].Header.Get("If-Modified-Since"))
+	})
+
+	t.Run("does not share entries between tokens", func(t *testing.T) {
+		requests = nil
+		do(t, http.MethodGet, "/etag", "token2")
+		require.Len(t, requests, 1)
+		assert.Empty(t, requests[0].Header.Get("If-None-Match"))
+	})
+
+	t.Run("does not cache other methods", func(t *testing.T) {
+		requests = nil
+		do(t, http.MethodPost, "/etag", "token3")
+		do(t, http.MethodPost, "/etag", "token3")
+		require.Len(t, requests, 2)
+		assert.Empty(t, requests[1].Header.Get("If-None-Match"))
+	})
+
+	t.Run("passes large responses through uncached", func(t *testing.T) {
+		requests = nil
+		_, body := do(t, http.MethodGet, "/large", "token1")
+		assert.Len(t, body, maxCachedResponseSize+1)
+		do(t, http.MethodGet, "/large", "token1")
+		require.Len(t, requests, 2)
+		assert.Empty(t, requests[1].Header.Get("If-None-Match"))
+	})
+}
+
+func Test_conditionalCacheTransport_Eviction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		_, _ = io.WriteString(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	transport := newConditionalCacheTransport(http.DefaultTransport, 25)
+	get := func(path string) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	get("/a")
+	get("/b")
+	get("/a")
+	get("/c")
+
+	assert.Equal(t, 20, transport.size)
+	assert.Len(t, transport.entries, 2)
+	for _, key := range []string{"/a", "/c"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+key, nil)
+		assert.Contains(t, transport.entries, cacheKey(req), "recently used entry %s must be kept", key)
+	}
+}
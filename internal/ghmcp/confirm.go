@@ -0,0 +1,135 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxSummaryValueLength bounds the length of the argument values shown when
+// asking for confirmation, so that file contents don't flood the prompt.
+const maxSummaryValueLength = 100
+
+// confirmationDecorator makes the tools for which requires returns true ask the
+// user for confirmation through MCP elicitation before they run. Calls that
+// aren't confirmed, including calls from clients that don't support
+// elicitation, fail without changing anything. Dry runs aren't confirmed, as
+// they don't change anything either.
+func confirmationDecorator(mcpServer *server.MCPServer, requires toolsets.ToolFilter) toolsets.ToolDecorator {
+	return func(tool server.ServerTool) server.ServerTool {
+		if !requires(tool.Tool) {
+			return tool
+		}
+
+		next := tool.Handler
+		title := tool.Tool.Name
+		if tool.Tool.Annotations.Title != "" {
+			title = tool.Tool.Annotations.Title
+		}
+		tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if isDryRun(ctx) {
+				return next(ctx, request)
+			}
+			if !supportsElicitation(ctx) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s requires confirmation by the user, but the client doesn't support elicitation", request.Params.Name)), nil
+			}
+
+			result, err := mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{
+				Params: mcp.ElicitationParams{
+					Message: fmt.Sprintf("%s?\n\n%s", title, confirmationSummary(request)),
+					RequestedSchema: map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"confirm": map[string]any{
+								"type":        "boolean",
+								"title":       "Confirm",
+								"description": "Run " + request.Params.Name + " with these arguments",
+							},
+						},
+						"required": []string{"confirm"},
+					},
+				},
+			})
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to ask the user for confirmation", err), nil
+			}
+			if !confirmed(result) {
+				return mcp.NewToolResultError(fmt.Sprintf("the user did not confirm %s, nothing was changed", request.Params.Name)), nil
+			}
+			return next(ctx, request)
+		}
+		return tool
+	}
+}
+
+// supportsElicitation reports whether the client of the session in ctx declared
+// that it supports elicitation.
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		// Sessions that don't keep the client capabilities can still try
+		_, ok := server.ClientSessionFromContext(ctx).(server.SessionWithElicitation)
+		return ok
+	}
+	return session.GetClientCapabilities().Elicitation != nil
+}
+
+func confirmed(result *mcp.ElicitationResult) bool {
+	if result == nil || result.Action != mcp.ElicitationResponseActionAccept {
+		return false
+	}
+	content, _ := result.Content.(map[string]any)
+	confirm, _ := content["confirm"].(bool)
+	return confirm
+}
+
+// confirmationSummary describes the target of a tool call, one argument per line.
+func confirmationSummary(request mcp.CallToolRequest) string {
+	arguments := request.GetArguments()
+	repository := repositoryArgument(arguments)
+	var lines []string
+	if repository != "" {
+		lines = append(lines, "repository: "+repository)
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		if name == dryRunArgument || (repository != "" && (name == "owner" || name == "repo")) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, name+": "+summaryValue(arguments[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func summaryValue(value any) string {
+	var text string
+	switch value := value.(type) {
+	case string:
+		text = value
+	case float64, bool:
+		text = fmt.Sprint(value)
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			text = fmt.Sprint(value)
+		} else {
+			text = string(encoded)
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if len([]rune(text)) > maxSummaryValueLength {
+		text = string([]rune(text)[:maxSummaryValueLength]) + "…"
+	}
+	return text
+}
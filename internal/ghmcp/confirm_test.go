@@ -0,0 +1,123 @@
+package ghmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitingSession is a session whose client answers elicitation requests with response.
+type elicitingSession struct {
+	testSession
+	capabilities mcp.ClientCapabilities
+	response     mcp.ElicitationResponse
+	requests     []mcp.ElicitationRequest
+}
+
+func (s *elicitingSession) GetClientInfo() mcp.Implementation                 { return mcp.Implementation{} }
+func (s *elicitingSession) SetClientInfo(mcp.Implementation)                  {}
+func (s *elicitingSession) GetClientCapabilities() mcp.ClientCapabilities     { return s.capabilities }
+func (s *elicitingSession) SetClientCapabilities(caps mcp.ClientCapabilities) { s.capabilities = caps }
+func (s *elicitingSession) RequestElicitation(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.requests = append(s.requests, request)
+	return &mcp.ElicitationResult{ElicitationResponse: s.response}, nil
+}
+
+func Test_confirmationDecorator(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0")
+	decorate := confirmationDecorator(srv, func(tool mcp.Tool) bool {
+		return tool.Name == "merge_pull_request"
+	})
+
+	var calls int
+	tool := decorate(server.ServerTool{
+		Tool: mcp.NewTool("merge_pull_request", mcp.WithToolAnnotation(mcp.ToolAnnotation{Title: "Merge pull request"})),
+		Handler: func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			return mcp.NewToolResultText("merged"), nil
+		},
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "merge_pull_request"
+	request.Params.Arguments = map[string]any{"owner": "octo-org", "repo": "app", "pullNumber": float64(7), "commit_message": strings.Repeat("x", 200)}
+
+	withElicitation := mcp.ClientCapabilities{Elicitation: &struct{}{}}
+	confirm := mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"confirm": true}}
+
+	tests := []struct {
+		name           string
+		session        *elicitingSession
+		dryRun         bool
+		expectedCalls  int
+		expectedErrMsg string
+	}{
+		{
+			name:          "confirmed",
+			session:       &elicitingSession{capabilities: withElicitation, response: confirm},
+			expectedCalls: 1,
+		},
+		{
+			name:           "declined",
+			session:        &elicitingSession{capabilities: withElicitation, response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline}},
+			expectedErrMsg: "the user did not confirm merge_pull_request, nothing was changed",
+		},
+		{
+			name:           "accepted without confirming",
+			session:        &elicitingSession{capabilities: withElicitation, response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"confirm": false}}},
+			expectedErrMsg: "the user did not confirm merge_pull_request, nothing was changed",
+		},
+		{
+			name:           "client without elicitation",
+			session:        &elicitingSession{response: confirm},
+			expectedErrMsg: "merge_pull_request requires confirmation by the user, but the client doesn't support elicitation",
+		},
+		{
+			name:          "dry run",
+			session:       &elicitingSession{capabilities: withElicitation},
+			dryRun:        true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = 0
+			ctx := srv.WithContext(context.Background(), tc.session)
+			if tc.dryRun {
+				ctx, _ = contextWithDryRunRecorder(ctx)
+			}
+
+			result, err := tool.Handler(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedErrMsg != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tc.expectedErrMsg, resultText(result))
+				return
+			}
+			assert.False(t, result.IsError)
+		})
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		session := &elicitingSession{capabilities: withElicitation, response: confirm}
+		_, err := tool.Handler(srv.WithContext(context.Background(), session), request)
+		require.NoError(t, err)
+		require.Len(t, session.requests, 1)
+		assert.Equal(t, "Merge pull request?\n\n"+
+			"repository: octo-org/app\n"+
+			"commit_message: "+strings.Repeat("x", maxSummaryValueLength)+"…\n"+
+			"pullNumber: 7", session.requests[0].Params.Message)
+	})
+
+	t.Run("other tools are unchanged", func(t *testing.T) {
+		other := decorate(server.ServerTool{Tool: mcp.NewTool("create_issue")})
+		assert.Nil(t, other.Handler)
+	})
+}
@@ -0,0 +1,542 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	gogithub "github.com/google/go-github/v74/github"
+)
+
+type DoctorConfig struct {
+	// Version of the server
+	Version string
+
+	// GitHub Host to diagnose (e.g. github.com or github.enterprise.com)
+	Host string
+
+	// APIURLs explicitly configures API endpoints, overriding those derived from Host
+	APIURLs APIURLs
+
+	// Network configures how connections to GitHub are made
+	Network NetworkConfig
+
+	// Token to diagnose, empty if none is configured
+	Token string
+
+	// AppID, AppPrivateKeyPath and AppInstallationID configure authentication as
+	// a GitHub App installation. When AppID is set, the installation token is
+	// diagnosed instead of Token.
+	AppID             int64
+	AppPrivateKeyPath string
+	AppInstallationID int64
+
+	// EnabledToolsets is a list of toolsets to probe access for
+	EnabledToolsets []string
+
+	// Repository is the owner/repo to probe the toolsets that act on
+	// repositories with, defaults to the repository the token can access that
+	// was pushed to last
+	Repository string
+
+	// JSON writes the report as JSON instead of text
+	JSON bool
+
+	// Output receives the report, defaults to os.Stdout
+	Output io.Writer
+}
+
+// doctorReport is what RunDoctor found out about the configuration.
+type doctorReport struct {
+	Host      doctorHost       `json:"host"`
+	Token     doctorToken      `json:"token"`
+	GraphQL   *doctorProbe     `json:"graphql,omitempty"`
+	RateLimit *doctorRateLimit `json:"rate_limit,omitempty"`
+	// Repository is the repository the toolsets were probed with
+	Repository string        `json:"repository,omitempty"`
+	Toolsets   []doctorProbe `json:"toolsets,omitempty"`
+	Problems   []string      `json:"problems"`
+}
+
+type doctorHost struct {
+	Product    string `json:"product"`
+	WebURL     string `json:"web_url"`
+	RESTURL    string `json:"rest_url"`
+	GraphQLURL string `json:"graphql_url"`
+	UploadURL  string `json:"upload_url"`
+	RawURL     string `json:"raw_url"`
+	// SubdomainIsolation is only reported for GitHub Enterprise Server
+	SubdomainIsolation *bool `json:"subdomain_isolation,omitempty"`
+}
+
+type doctorToken struct {
+	Configured bool   `json:"configured"`
+	Valid      bool   `json:"valid"`
+	Type       string `json:"type,omitempty"`
+	Login      string `json:"login,omitempty"`
+	// Scopes are only known for classic personal access tokens and OAuth tokens
+	Scopes []string `json:"scopes,omitempty"`
+	// Permissions are only known for GitHub App installations
+	Permissions map[string]string `json:"permissions,omitempty"`
+	ExpiresAt   string            `json:"expires_at,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+type doctorRateLimit struct {
+	REST    *gogithub.Rate `json:"rest,omitempty"`
+	GraphQL *gogithub.Rate `json:"graphql,omitempty"`
+	// Disabled is set when the instance doesn't limit the rate of requests
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+type doctorProbe struct {
+	Toolset string `json:"toolset,omitempty"`
+	Request string `json:"request,omitempty"`
+	OK      bool   `json:"ok"`
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// toolsetProbe is a request confirming access to the API a toolset uses. In paths
+// and queries, {login} stands for the user the token belongs to, {repo} for
+// owner/repo and {owner} and {name} for its parts.
+type toolsetProbe struct {
+	rest    string
+	graphql string
+}
+
+func (p toolsetProbe) needsRepository() bool {
+	return strings.Contains(p.rest+p.graphql, "{repo}") || strings.Contains(p.graphql, "{owner}")
+}
+
+func (p toolsetProbe) needsUser() bool {
+	return strings.Contains(p.rest+p.graphql, "{login}")
+}
+
+var toolsetProbes = map[string]toolsetProbe{
+	github.ToolsetMetadataContext.ID:            {rest: "user"},
+	github.ToolsetMetadataRepos.ID:              {rest: "repos/{repo}/commits?per_page=1"},
+	github.ToolsetMetadataIssues.ID:             {rest: "repos/{repo}/issues?per_page=1"},
+	github.ToolsetMetadataPullRequests.ID:       {rest: "repos/{repo}/pulls?per_page=1"},
+	github.ToolsetMetadataUsers.ID:              {rest: "search/users?q=user:{login}&per_page=1"},
+	github.ToolsetMetadataOrgs.ID:               {rest: "user/orgs?per_page=1"},
+	github.ToolsetMetadataActions.ID:            {rest: "repos/{repo}/actions/workflows?per_page=1"},
+	github.ToolsetMetadataCodeSecurity.ID:       {rest: "repos/{repo}/code-scanning/alerts?per_page=1"},
+	github.ToolsetMetadataSecretProtection.ID:   {rest: "repos/{repo}/secret-scanning/alerts?per_page=1"},
+	github.ToolsetMetadataDependabot.ID:         {rest: "repos/{repo}/dependabot/alerts?per_page=1"},
+	github.ToolsetMetadataNotifications.ID:      {rest: "notifications?per_page=1"},
+	github.ToolsetMetadataDiscussions.ID:        {graphql: `query { repository(owner: "{owner}", name: "{name}") { discussions(first: 1) { totalCount } } }`},
+	github.ToolsetMetadataGists.ID:              {rest: "gists?per_page=1"},
+	github.ToolsetMetadataSecurityAdvisories.ID: {rest: "advisories?per_page=1"},
+	github.ToolsetMetadataProjects.ID:           {rest: "users/{login}/projectsV2?per_page=1"},
+	github.ToolsetMetadataStargazers.ID:         {rest: "user/starred?per_page=1"},
+	github.ToolsetLabels.ID:                     {rest: "repos/{repo}/labels?per_page=1"},
+}
+
+// unprobedToolsets are the toolsets without a probe, with the reason why.
+var unprobedToolsets = map[string]string{
+	github.ToolsetMetadataExperiments.ID: "has no tools",
+	github.ToolsetMetadataDynamic.ID:     "doesn't use the API directly",
+}
+
+// errDoctorProblems is returned by RunDoctor when it found problems.
+var errDoctorProblems = errors.New("problems found")
+
+// RunDoctor checks the host, token and network configuration the server would
+// run with, and probes access to the API of each enabled toolset, writing a
+// report of what it found. It returns an error if it found problems.
+func RunDoctor(cfg DoctorConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	output := cfg.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	transport, err := newBaseTransport(cfg.Network)
+	if err != nil {
+		return fmt.Errorf("failed to configure network: %w", err)
+	}
+	apiHost, err := parseAPIHost(cfg.Host, cfg.APIURLs, transport)
+	if err != nil {
+		return fmt.Errorf("failed to parse API host: %w", err)
+	}
+
+	report := diagnose(ctx, cfg, apiHost, transport)
+	if cfg.JSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		writeDoctorReport(output, report)
+	}
+
+	if len(report.Problems) > 0 {
+		return errDoctorProblems
+	}
+	return nil
+}
+
+func diagnose(ctx context.Context, cfg DoctorConfig, apiHost apiHost, transport http.RoundTripper) *doctorReport {
+	report := &doctorReport{
+		Host: doctorHost{
+			Product:    apiHost.product,
+			WebURL:     apiHost.webURL.String(),
+			RESTURL:    apiHost.baseRESTURL.String(),
+			GraphQLURL: apiHost.graphqlURL.String(),
+			UploadURL:  apiHost.uploadURL.String(),
+			RawURL:     apiHost.rawURL.String(),
+		},
+		Problems: []string{},
+	}
+	if apiHost.product == "GitHub Enterprise Server" {
+		report.Host.SubdomainIsolation = &apiHost.subdomainIsolation
+	}
+
+	if cfg.Token == "" && cfg.AppID == 0 {
+		report.Problems = append(report.Problems, "no token is configured, set GITHUB_PERSONAL_ACCESS_TOKEN or run `github-mcp-server login`")
+		return report
+	}
+
+	httpClient := &http.Client{Transport: &userAgentTransport{transport: transport, agent: fmt.Sprintf("github-mcp-server/%s", cfg.Version)}}
+	token := cfg.Token
+	if cfg.AppID != 0 {
+		// Installation tokens are created the way the server creates them, and
+		// belong to no user
+		report.Token = doctorToken{Configured: true, Type: "GitHub App installation token"}
+		source, err := newAppTokenSource(cfg.AppID, cfg.AppInstallationID, cfg.AppPrivateKeyPath, apiHost, httpClient.Transport)
+		if err == nil {
+			token, err = source.Token(ctx)
+		}
+		if err == nil {
+			report.Token.Permissions, err = source.Permissions(ctx)
+		}
+		if err != nil {
+			report.Token.Error = describeAPIError(err)
+			report.Problems = append(report.Problems, "no installation token can be created for the GitHub App: "+report.Token.Error)
+			return report
+		}
+		report.Token.Valid = true
+	}
+
+	client := gogithub.NewClient(httpClient).WithAuthToken(token)
+	client.BaseURL = apiHost.baseRESTURL
+	client.UploadURL = apiHost.uploadURL
+
+	if cfg.AppID == 0 {
+		report.Token = doctorToken{Configured: true, Type: tokenType(token)}
+		user, resp, err := client.Users.Get(ctx, "")
+		if err != nil {
+			report.Token.Error = describeAPIError(err)
+			report.Problems = append(report.Problems, tokenProblem(err, apiHost))
+			return report
+		}
+		report.Token.Valid = true
+		report.Token.Login = user.GetLogin()
+		report.Token.Scopes = github.ParseOAuthScopes(resp.Header.Values("X-OAuth-Scopes"))
+		report.Token.ExpiresAt = resp.Header.Get("GitHub-Authentication-Token-Expiration")
+	}
+
+	graphqlClient := &http.Client{Transport: &bearerAuthTransport{transport: httpClient.Transport, token: token}}
+	report.GraphQL = &doctorProbe{Request: "POST " + apiHost.graphqlURL.Path}
+	if err := graphQLProbe(ctx, graphqlClient, apiHost.graphqlURL.String(), "query { __typename }"); err != nil {
+		report.GraphQL.Error = err.Error()
+		report.Problems = append(report.Problems, fmt.Sprintf("the GraphQL API at %s can't be used: %s", apiHost.graphqlURL, err))
+	} else {
+		report.GraphQL.OK = true
+	}
+
+	report.RateLimit = &doctorRateLimit{}
+	limits, _, err := client.RateLimit.Get(ctx)
+	switch {
+	case isNotFound(err):
+		report.RateLimit.Disabled = true
+	case err != nil:
+		report.Problems = append(report.Problems, "failed to get the rate limit: "+describeAPIError(err))
+	default:
+		report.RateLimit.REST = limits.GetCore()
+		report.RateLimit.GraphQL = limits.GetGraphQL()
+		for _, limit := range []struct {
+			name string
+			rate *gogithub.Rate
+		}{{"REST", limits.GetCore()}, {"GraphQL", limits.GetGraphQL()}} {
+			if limit.rate != nil && limit.rate.Limit > 0 && limit.rate.Remaining == 0 {
+				report.Problems = append(report.Problems, fmt.Sprintf("the %s API rate limit is exhausted until %s", limit.name, limit.rate.Reset.UTC().Format(time.RFC3339)))
+			}
+		}
+	}
+
+	enabledToolsets := resolveToolsets(MCPServerConfig{EnabledToolsets: cfg.EnabledToolsets})
+	if github.ContainsToolset(enabledToolsets, github.ToolsetMetadataAll.ID) {
+		enabledToolsets = nil
+		for _, toolset := range github.AvailableTools() {
+			enabledToolsets = append(enabledToolsets, toolset.ID)
+		}
+	}
+
+	repository := cfg.Repository
+	switch {
+	case repository != "":
+	case cfg.AppID != 0:
+		installation, _, err := client.Apps.ListRepos(ctx, &gogithub.ListOptions{PerPage: 1})
+		if err == nil && len(installation.Repositories) > 0 {
+			repository = installation.Repositories[0].GetFullName()
+		}
+	default:
+		repositories, _, err := client.Repositories.ListByAuthenticatedUser(ctx, &gogithub.RepositoryListByAuthenticatedUserOptions{
+			Sort:        "pushed",
+			ListOptions: gogithub.ListOptions{PerPage: 1},
+		})
+		if err == nil && len(repositories) > 0 {
+			repository = repositories[0].GetFullName()
+		}
+	}
+	report.Repository = repository
+	owner, name, _ := strings.Cut(repository, "/")
+	placeholders := strings.NewReplacer("{login}", report.Token.Login, "{repo}", repository, "{owner}", owner, "{name}", name)
+
+	for _, toolset := range enabledToolsets {
+		probe := doctorProbe{Toolset: toolset}
+		definition, ok := toolsetProbes[toolset]
+		var err error
+		switch {
+		case !ok:
+			probe.Skipped = unprobedToolsets[toolset]
+		case definition.needsRepository() && repository == "":
+			probe.Skipped = "the token can't access any repository to probe with, pass --repo"
+		case definition.needsUser() && report.Token.Login == "":
+			probe.Skipped = "GitHub App installations don't act as a user"
+		case definition.graphql != "":
+			probe.Request = "POST " + apiHost.graphqlURL.Path
+			err = graphQLProbe(ctx, graphqlClient, apiHost.graphqlURL.String(), placeholders.Replace(definition.graphql))
+		default:
+			path := placeholders.Replace(definition.rest)
+			probe.Request = "GET /" + strings.TrimPrefix(apiHost.baseRESTURL.Path+path, "/")
+			var req *http.Request
+			if req, err = client.NewRequest(http.MethodGet, path, nil); err == nil {
+				_, err = client.Do(ctx, req, nil)
+			}
+		}
+		if probe.Skipped == "" {
+			if err != nil {
+				probe.Error = describeAPIError(err)
+				report.Problems = append(report.Problems, fmt.Sprintf("the %s toolset can't be used: %s", toolset, probe.Error))
+			} else {
+				probe.OK = true
+			}
+		}
+		report.Toolsets = append(report.Toolsets, probe)
+	}
+
+	return report
+}
+
+// tokenType names the kind of token by its prefix.
+func tokenType(token string) string {
+	switch {
+	case strings.HasPrefix(token, "ghp_"):
+		return "classic personal access token"
+	case strings.HasPrefix(token, "github_pat_"):
+		return "fine-grained personal access token"
+	case strings.HasPrefix(token, "gho_"):
+		return "OAuth token"
+	case strings.HasPrefix(token, "ghu_"):
+		return "GitHub App user token"
+	case strings.HasPrefix(token, "ghs_"):
+		return "GitHub App installation token"
+	default:
+		return "unknown"
+	}
+}
+
+// tokenProblem explains why the token couldn't be used to get the authenticated user.
+func tokenProblem(err error, apiHost apiHost) string {
+	var errorResponse *gogithub.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response == nil {
+		return fmt.Sprintf("the REST API at %s can't be reached: %s", apiHost.baseRESTURL, err)
+	}
+	switch errorResponse.Response.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Sprintf("the token was rejected by %s, it is invalid, expired or revoked, or belongs to another GitHub instance", apiHost.webURL.Host)
+	case http.StatusNotFound:
+		return fmt.Sprintf("%s answered 404 Not Found for the authenticated user, check that --gh-host and the API URLs point at the GitHub instance the token belongs to", apiHost.baseRESTURL)
+	default:
+		return "the token can't be used: " + describeAPIError(err)
+	}
+}
+
+// describeAPIError describes err briefly, adding the scopes GitHub reports the
+// request would have required.
+func describeAPIError(err error) string {
+	var errorResponse *gogithub.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response == nil {
+		return err.Error()
+	}
+	description := errorResponse.Response.Status
+	if errorResponse.Message != "" {
+		description += ": " + errorResponse.Message
+	}
+	if scopes := errorResponse.Response.Header.Get("X-Accepted-OAuth-Scopes"); scopes != "" {
+		description += " (requires scopes: " + scopes + ")"
+	}
+	return description
+}
+
+func isNotFound(err error) bool {
+	var errorResponse *gogithub.ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound
+}
+
+// graphQLProbe sends query to the GraphQL API, returning the first error.
+func graphQLProbe(ctx context.Context, client *http.Client, url string, query string) error {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return errors.New(result.Errors[0].Message)
+	}
+	return nil
+}
+
+func writeDoctorReport(output io.Writer, report *doctorReport) {
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	line := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(w, format+"\n", args...)
+	}
+	status := func(ok bool) string {
+		if ok {
+			return "ok"
+		}
+		return "FAIL"
+	}
+
+	line("Host: %s", report.Host.Product)
+	line("  Web:\t%s", report.Host.WebURL)
+	line("  REST API:\t%s", report.Host.RESTURL)
+	line("  GraphQL API:\t%s", report.Host.GraphQLURL)
+	line("  Uploads:\t%s", report.Host.UploadURL)
+	line("  Raw content:\t%s", report.Host.RawURL)
+	if report.Host.SubdomainIsolation != nil {
+		detected := "not detected"
+		if *report.Host.SubdomainIsolation {
+			detected = "detected"
+		}
+		line("  Subdomain isolation:\t%s", detected)
+	}
+
+	if report.Token.Configured {
+		line("")
+		line("Token: %s", report.Token.Type)
+		if report.Token.Valid {
+			if report.Token.Login != "" {
+				line("  User:\t%s", report.Token.Login)
+			}
+			if report.Token.Scopes != nil {
+				line("  Scopes:\t%s", strings.Join(report.Token.Scopes, ", "))
+			}
+			if report.Token.Permissions != nil {
+				permissions := make([]string, 0, len(report.Token.Permissions))
+				for resource, access := range report.Token.Permissions {
+					permissions = append(permissions, resource+": "+access)
+				}
+				sort.Strings(permissions)
+				line("  Permissions:\t%s", strings.Join(permissions, ", "))
+			}
+			if report.Token.ExpiresAt != "" {
+				line("  Expires:\t%s", report.Token.ExpiresAt)
+			}
+		} else {
+			line("  Error:\t%s", report.Token.Error)
+		}
+	}
+	if report.GraphQL != nil {
+		line("  GraphQL API:\t%s", status(report.GraphQL.OK))
+	}
+
+	if report.RateLimit != nil {
+		line("")
+		line("Rate limit:")
+		if report.RateLimit.Disabled {
+			line("  disabled on this instance")
+		}
+		for _, rate := range []struct {
+			name string
+			rate *gogithub.Rate
+		}{{"REST", report.RateLimit.REST}, {"GraphQL", report.RateLimit.GraphQL}} {
+			if rate.rate != nil {
+				line("  %s:\t%d of %d remaining, resets at %s", rate.name, rate.rate.Remaining, rate.rate.Limit, rate.rate.Reset.Local().Format("15:04 MST"))
+			}
+		}
+	}
+
+	if len(report.Toolsets) > 0 {
+		line("")
+		if report.Repository != "" {
+			line("Toolsets, probed with %s:", report.Repository)
+		} else {
+			line("Toolsets:")
+		}
+		for _, probe := range report.Toolsets {
+			switch {
+			case probe.Skipped != "":
+				line("  %s\tskipped\t%s", probe.Toolset, probe.Skipped)
+			case probe.OK:
+				line("  %s\tok\t%s", probe.Toolset, probe.Request)
+			default:
+				line("  %s\tFAIL\t%s: %s", probe.Toolset, probe.Request, probe.Error)
+			}
+		}
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintln(output)
+	if len(report.Problems) == 0 {
+		_, _ = fmt.Fprintln(output, "No problems found.")
+		return
+	}
+	if len(report.Problems) == 1 {
+		_, _ = fmt.Fprintln(output, "1 problem found:")
+	} else {
+		_, _ = fmt.Fprintf(output, "%d problems found:\n", len(report.Problems))
+	}
+	for _, problem := range report.Problems {
+		_, _ = fmt.Fprintf(output, "  - %s\n", problem)
+	}
+}
@@ -0,0 +1,207 @@
+package ghmcp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doctorAPI is a stand-in for the GitHub API that answers the requests of RunDoctor.
+func doctorAPI(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp_valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		w.Header().Set("GitHub-Authentication-Token-Expiration", "2030-01-01 00:00:00 UTC")
+		_, _ = io.WriteString(w, `{"login":"octocat"}`)
+	})
+	mux.HandleFunc("GET /api/v3/rate_limit", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"resources":{"core":{"limit":5000,"remaining":4990,"reset":1893456000},"graphql":{"limit":5000,"remaining":0,"reset":1893456000}}}`)
+	})
+	mux.HandleFunc("GET /api/v3/user/repos", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"full_name":"octocat/hello-world"}]`)
+	})
+	mux.HandleFunc("GET /api/v3/repos/octocat/hello-world/issues", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[]`)
+	})
+	mux.HandleFunc("GET /api/v3/repos/octocat/hello-world/actions/workflows", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Accepted-OAuth-Scopes", "workflow")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"message":"Resource not accessible by personal access token"}`)
+	})
+	mux.HandleFunc("POST /api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"token":"ghs_installation","expires_at":"2030-01-01T00:00:00Z","permissions":{"issues":"write","metadata":"read"}}`)
+	})
+	mux.HandleFunc("GET /api/v3/installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghs_installation" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"total_count":1,"repositories":[{"full_name":"octo-org/app"}]}`)
+	})
+	mux.HandleFunc("GET /api/v3/repos/octo-org/app/issues", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[]`)
+	})
+	mux.HandleFunc("POST /api/graphql", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"data":{"__typename":"Query"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func Test_RunDoctor(t *testing.T) {
+	srv := doctorAPI(t)
+	run := func(token string, json bool) (string, error) {
+		var output bytes.Buffer
+		err := RunDoctor(DoctorConfig{
+			Version:         "test",
+			APIURLs:         APIURLs{REST: srv.URL + "/api/v3/"},
+			Token:           token,
+			EnabledToolsets: []string{"issues", "actions", "experiments"},
+			JSON:            json,
+			Output:          &output,
+		})
+		return output.String(), err
+	}
+
+	t.Run("report", func(t *testing.T) {
+		output, err := run("ghp_valid", true)
+		assert.ErrorIs(t, err, errDoctorProblems)
+
+		var report doctorReport
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Equal(t, "custom endpoints", report.Host.Product)
+		assert.Equal(t, srv.URL+"/api/graphql", report.Host.GraphQLURL)
+		assert.Equal(t, doctorToken{
+			Configured: true,
+			Valid:      true,
+			Type:       "classic personal access token",
+			Login:      "octocat",
+			Scopes:     []string{"repo", "read:org"},
+			ExpiresAt:  "2030-01-01 00:00:00 UTC",
+		}, report.Token)
+		assert.True(t, report.GraphQL.OK)
+		assert.Equal(t, 4990, report.RateLimit.REST.Remaining)
+		assert.Equal(t, "octocat/hello-world", report.Repository)
+		assert.Equal(t, []doctorProbe{
+			{Toolset: "issues", Request: "GET /api/v3/repos/octocat/hello-world/issues?per_page=1", OK: true},
+			{Toolset: "actions", Request: "GET /api/v3/repos/octocat/hello-world/actions/workflows?per_page=1", Error: "403 Forbidden: Resource not accessible by personal access token (requires scopes: workflow)"},
+			{Toolset: "experiments", Skipped: "has no tools"},
+		}, report.Toolsets)
+		assert.Equal(t, []string{
+			"the GraphQL API rate limit is exhausted until 2030-01-01T00:00:00Z",
+			"the actions toolset can't be used: 403 Forbidden: Resource not accessible by personal access token (requires scopes: workflow)",
+		}, report.Problems)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		output, err := run("ghp_valid", false)
+		assert.ErrorIs(t, err, errDoctorProblems)
+		assert.Contains(t, output, "REST API:     "+srv.URL+"/api/v3/")
+		assert.Contains(t, output, "User:         octocat")
+		assert.Contains(t, output, "Toolsets, probed with octocat/hello-world:")
+		assert.Contains(t, output, "2 problems found:")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		output, err := run("ghp_invalid", true)
+		assert.ErrorIs(t, err, errDoctorProblems)
+
+		var report doctorReport
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.False(t, report.Token.Valid)
+		assert.Equal(t, "401 Unauthorized: Bad credentials", report.Token.Error)
+		assert.Empty(t, report.Toolsets)
+		assert.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0], "the token was rejected")
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		output, err := run("", false)
+		assert.ErrorIs(t, err, errDoctorProblems)
+		assert.Contains(t, output, "no token is configured")
+	})
+}
+
+func Test_RunDoctor_App(t *testing.T) {
+	srv := doctorAPI(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	var output bytes.Buffer
+	err = RunDoctor(DoctorConfig{
+		Version:           "test",
+		APIURLs:           APIURLs{REST: srv.URL + "/api/v3/"},
+		AppID:             1,
+		AppPrivateKeyPath: keyPath,
+		AppInstallationID: 42,
+		EnabledToolsets:   []string{"issues", "projects"},
+		JSON:              true,
+		Output:            &output,
+	})
+	assert.ErrorIs(t, err, errDoctorProblems, "the GraphQL rate limit is exhausted")
+
+	var report doctorReport
+	require.NoError(t, json.Unmarshal(output.Bytes(), &report))
+	assert.Equal(t, doctorToken{
+		Configured:  true,
+		Valid:       true,
+		Type:        "GitHub App installation token",
+		Permissions: map[string]string{"issues": "write", "metadata": "read"},
+	}, report.Token)
+	assert.True(t, report.GraphQL.OK)
+	assert.Equal(t, "octo-org/app", report.Repository)
+	assert.Equal(t, []doctorProbe{
+		{Toolset: "issues", Request: "GET /api/v3/repos/octo-org/app/issues?per_page=1", OK: true},
+		{Toolset: "projects", Skipped: "GitHub App installations don't act as a user"},
+	}, report.Toolsets)
+
+	t.Run("text", func(t *testing.T) {
+		var output bytes.Buffer
+		writeDoctorReport(&output, &report)
+		assert.Contains(t, output.String(), "Permissions:  issues: write, metadata: read")
+		assert.NotContains(t, output.String(), "User:")
+	})
+}
+
+func Test_toolsetProbes(t *testing.T) {
+	// Toolsets get a probe, or a reason why they have none
+	tsg := github.DefaultToolsetGroup(false, nil, nil, nil, nil, translations.NullTranslationHelper, 5000)
+	ids := map[string]bool{}
+	for id := range tsg.Toolsets {
+		ids[id] = true
+	}
+	for _, toolset := range github.AvailableTools() {
+		ids[toolset.ID] = true
+	}
+
+	for id := range ids {
+		_, probed := toolsetProbes[id]
+		_, exempt := unprobedToolsets[id]
+		assert.True(t, probed != exempt, "toolset %s needs either a probe in toolsetProbes or a reason in unprobedToolsets", id)
+	}
+	for id := range toolsetProbes {
+		assert.True(t, ids[id], "probe of unknown toolset %s", id)
+	}
+}
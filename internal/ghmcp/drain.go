@@ -0,0 +1,109 @@
+package ghmcp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultShutdownGracePeriod is how long tool calls in flight at shutdown get to
+// finish when no grace period is configured.
+const defaultShutdownGracePeriod = 30 * time.Second
+
+// shutdownGracePeriod returns the configured grace period, or the default when none is configured.
+func shutdownGracePeriod(configured time.Duration) time.Duration {
+	if configured <= 0 {
+		return defaultShutdownGracePeriod
+	}
+	return configured
+}
+
+// inFlightCall is a tool call that has not returned yet.
+type inFlightCall struct {
+	tool    string
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+// callTracker keeps track of the tool calls in flight, so that shutdown can wait
+// for them instead of cutting off a sequence of writes halfway through.
+type callTracker struct {
+	mu       sync.Mutex
+	draining bool
+	calls    map[*inFlightCall]struct{}
+	wg       sync.WaitGroup
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{calls: map[*inFlightCall]struct{}{}}
+}
+
+// middleware tracks the tool calls it passes on, and rejects new calls once the
+// tracker is draining.
+func (t *callTracker) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		call := &inFlightCall{tool: request.Params.Name, started: time.Now(), cancel: cancel}
+
+		t.mu.Lock()
+		if t.draining {
+			t.mu.Unlock()
+			return mcp.NewToolResultError("the server is shutting down, try again once it is back"), nil
+		}
+		t.calls[call] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		defer func() {
+			t.mu.Lock()
+			delete(t.calls, call)
+			t.mu.Unlock()
+			t.wg.Done()
+		}()
+		return next(ctx, request)
+	}
+}
+
+// drain stops new tool calls and waits up to gracePeriod for the calls in flight
+// to finish. Calls still running after that are cancelled and logged.
+func (t *callTracker) drain(gracePeriod time.Duration, logger *slog.Logger) {
+	t.mu.Lock()
+	t.draining = true
+	inFlight := len(t.calls)
+	t.mu.Unlock()
+	if inFlight == 0 {
+		return
+	}
+
+	logger.Info("waiting for tool calls to finish", "calls", inFlight, "gracePeriod", gracePeriod)
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("tool calls finished")
+		return
+	case <-time.After(gracePeriod):
+	}
+
+	t.mu.Lock()
+	for call := range t.calls {
+		logger.Warn("cancelled tool call still running at shutdown", "tool", call.tool, "duration", time.Since(call.started))
+		call.cancel()
+	}
+	t.mu.Unlock()
+
+	// Give the cancelled calls a moment to return their results
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+}
@@ -0,0 +1,82 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_callTracker(t *testing.T) {
+	callTool := func(tracker *callTracker, name string, handler func(context.Context) error) chan *mcp.CallToolResult {
+		results := make(chan *mcp.CallToolResult, 1)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		started := make(chan struct{})
+		go func() {
+			result, _ := tracker.middleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				close(started)
+				if err := handler(ctx); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				return mcp.NewToolResultText("done"), nil
+			})(context.Background(), request)
+			results <- result
+		}()
+		select {
+		case <-started:
+		case result := <-results:
+			// Rejected without running
+			results <- result
+		}
+		return results
+	}
+
+	t.Run("calls in flight finish", func(t *testing.T) {
+		tracker := newCallTracker()
+		release := make(chan struct{})
+		results := callTool(tracker, "push_files", func(context.Context) error {
+			<-release
+			return nil
+		})
+
+		drained := make(chan struct{})
+		go func() {
+			tracker.drain(time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			close(drained)
+		}()
+
+		require.Eventually(t, func() bool {
+			tracker.mu.Lock()
+			defer tracker.mu.Unlock()
+			return tracker.draining
+		}, time.Second, time.Millisecond)
+		rejected := <-callTool(tracker, "create_issue", func(context.Context) error { return nil })
+		assert.True(t, rejected.IsError, "new calls are rejected while draining")
+
+		close(release)
+		<-drained
+		assert.False(t, (<-results).IsError)
+	})
+
+	t.Run("calls exceeding the grace period are cancelled", func(t *testing.T) {
+		tracker := newCallTracker()
+		results := callTool(tracker, "push_files", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		var logs bytes.Buffer
+		tracker.drain(10*time.Millisecond, slog.New(slog.NewTextHandler(&logs, nil)))
+		result := <-results
+		assert.True(t, result.IsError)
+		assert.Contains(t, logs.String(), "cancelled tool call still running at shutdown")
+		assert.Contains(t, logs.String(), "tool=push_files")
+	})
+}
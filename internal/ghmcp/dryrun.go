@@ -0,0 +1,214 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dryRunArgument is the argument asking a single write tool call to be a dry run.
+const dryRunArgument = "dry_run"
+
+// dryRunRequest describes a request a dry run didn't send.
+type dryRunRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Body is the decoded JSON body, or a description of other bodies
+	Body any `json:"body,omitempty"`
+}
+
+// dryRunResult is the result of a write tool called in dry-run mode.
+type dryRunResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Requests []*dryRunRequest `json:"requests"`
+	Note     string           `json:"note"`
+}
+
+// dryRunRecorder captures the mutating requests of a tool call in the order
+// they were made.
+type dryRunRecorder struct {
+	mu       sync.Mutex
+	requests []*dryRunRequest
+}
+
+func (r *dryRunRecorder) record(request *dryRunRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, request)
+}
+
+func (r *dryRunRecorder) get() []*dryRunRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*dryRunRequest(nil), r.requests...)
+}
+
+type dryRunRecorderCtxKey struct{}
+
+func contextWithDryRunRecorder(ctx context.Context) (context.Context, *dryRunRecorder) {
+	recorder := &dryRunRecorder{}
+	return context.WithValue(ctx, dryRunRecorderCtxKey{}, recorder), recorder
+}
+
+func dryRunRecorderFromContext(ctx context.Context) *dryRunRecorder {
+	recorder, _ := ctx.Value(dryRunRecorderCtxKey{}).(*dryRunRecorder)
+	return recorder
+}
+
+// isDryRun reports whether the tool call ctx belongs to is a dry run.
+func isDryRun(ctx context.Context) bool {
+	return dryRunRecorderFromContext(ctx) != nil
+}
+
+// dryRunTransport keeps the mutating requests of tool calls in dry-run mode from
+// being sent, recording them and answering them with a synthetic success, so
+// that tools making several changes carry on and every change is recorded.
+// Reads still go through, so that a dry run validates its target and resolves
+// IDs the way a real call would.
+type dryRunTransport struct {
+	transport http.RoundTripper
+}
+
+func newDryRunTransport(transport http.RoundTripper) *dryRunTransport {
+	return &dryRunTransport{transport: transport}
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := dryRunRecorderFromContext(req.Context())
+	if recorder == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.transport.RoundTrip(req)
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	request := &dryRunRequest{Method: req.Method, URL: req.URL.String()}
+	var object map[string]any
+	if len(body) > 0 {
+		var decoded any
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			request.Body = decoded
+			object, _ = decoded.(map[string]any)
+		} else {
+			request.Body = map[string]any{"content_type": req.Header.Get("Content-Type"), "size": len(body)}
+		}
+	}
+
+	// GraphQL queries are sent as POST requests too, only mutations are held back
+	query, graphQL := object["query"].(string)
+	if graphQL && !isGraphQLMutation(query) {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return t.transport.RoundTrip(req)
+	}
+
+	recorder.record(request)
+	return dryRunResponse(req, graphQL), nil
+}
+
+// dryRunResponse returns the synthetic response to a mutating request held back
+// by a dry run: the status of a success with an empty object, or empty data for
+// GraphQL mutations. The body of the request isn't echoed, as the resources
+// GitHub returns are shaped differently. Values only GitHub can provide, like
+// IDs and SHAs, are missing.
+func dryRunResponse(req *http.Request, graphQL bool) *http.Response {
+	status, content := http.StatusOK, []byte("{}")
+	switch {
+	case graphQL:
+		content = []byte(`{"data":{}}`)
+	case req.Method == http.MethodDelete:
+		status, content = http.StatusNoContent, nil
+	case req.Method == http.MethodPost:
+		status = http.StatusCreated
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(content)),
+		ContentLength: int64(len(content)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the body of req without consuming it for later use.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	return body, err
+}
+
+func isGraphQLMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// dryRunDecorator makes write tools stop short of changing anything when they
+// are called in dry-run mode, returning the requests they would have sent
+// instead. When serverWide is set every call is a dry run, otherwise calls opt
+// in with the dry_run argument, which is added to the tools.
+func dryRunDecorator(serverWide bool) toolsets.ToolDecorator {
+	return func(tool server.ServerTool) server.ServerTool {
+		if !serverWide {
+			properties := maps.Clone(tool.Tool.InputSchema.Properties)
+			if properties == nil {
+				properties = map[string]any{}
+			}
+			properties[dryRunArgument] = map[string]any{
+				"type":        "boolean",
+				"description": "Validate the call and describe the change it would make, without making it",
+			}
+			tool.Tool.InputSchema.Properties = properties
+		}
+
+		next := tool.Handler
+		tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if dryRun, _ := request.GetArguments()[dryRunArgument].(bool); !serverWide && !dryRun {
+				return next(ctx, request)
+			}
+
+			ctx, recorder := contextWithDryRunRecorder(ctx)
+			result, err := next(ctx, request)
+			captured := recorder.get()
+			if len(captured) == 0 {
+				// The call made no change, e.g. because its arguments were invalid
+				return result, err
+			}
+
+			text, err := json.Marshal(dryRunResult{
+				DryRun:   true,
+				Requests: captured,
+				Note:     "No changes were made. The responses to these requests were simulated, so values only GitHub assigns, like IDs and SHAs, are missing from later requests.",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(string(text)), nil
+		}
+		return tool
+	}
+}
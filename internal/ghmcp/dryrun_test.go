@@ -0,0 +1,209 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingServer is a stand-in for the GitHub API that records the requests it receives.
+func recordingServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+func Test_dryRunTransport(t *testing.T) {
+	srv, requests := recordingServer(t)
+	client := &http.Client{Transport: newDryRunTransport(http.DefaultTransport)}
+
+	send := func(ctx context.Context, method, path, body string) (int, string) {
+		req, err := http.NewRequestWithContext(ctx, method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(content)
+	}
+
+	status, _ := send(context.Background(), http.MethodPost, "/repos/octocat/hello-world/issues", `{"title":"Bug"}`)
+	assert.Equal(t, http.StatusCreated, status, "requests outside dry runs are sent")
+
+	ctx, recorder := contextWithDryRunRecorder(context.Background())
+	send(ctx, http.MethodGet, "/repos/octocat/hello-world/issues/1", "")
+	send(ctx, http.MethodPost, "/graphql", `{"query":"query($owner:String!){repository(owner:$owner){id}}"}`)
+
+	status, body := send(ctx, http.MethodPost, "/repos/octocat/hello-world/issues", `{"title":"Bug"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.JSONEq(t, `{}`, body)
+	status, _ = send(ctx, http.MethodPatch, "/repos/octocat/hello-world/issues/1", `{"state":"closed"}`)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = send(ctx, http.MethodDelete, "/repos/octocat/hello-world/labels/bug", "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, body = send(ctx, http.MethodPost, "/graphql", `{"query":"mutation($input:AddSubIssueInput!){addSubIssue(input:$input){clientMutationId}}"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"data":{}}`, body)
+
+	assert.Equal(t, []string{
+		"POST /repos/octocat/hello-world/issues",
+		"GET /repos/octocat/hello-world/issues/1",
+		"POST /graphql",
+	}, requests())
+
+	assert.Equal(t, []*dryRunRequest{
+		{Method: http.MethodPost, URL: srv.URL + "/repos/octocat/hello-world/issues", Body: map[string]any{"title": "Bug"}},
+		{Method: http.MethodPatch, URL: srv.URL + "/repos/octocat/hello-world/issues/1", Body: map[string]any{"state": "closed"}},
+		{Method: http.MethodDelete, URL: srv.URL + "/repos/octocat/hello-world/labels/bug"},
+		{Method: http.MethodPost, URL: srv.URL + "/graphql", Body: map[string]any{"query": "mutation($input:AddSubIssueInput!){addSubIssue(input:$input){clientMutationId}}"}},
+	}, recorder.get(), "every mutation is recorded in order")
+}
+
+func Test_newGitHubServer_dryRun(t *testing.T) {
+	srv, requests := recordingServer(t)
+
+	newServer := func(dryRun bool) *gitHubServer {
+		ghServer, err := newGitHubServer(MCPServerConfig{
+			Version:           "test",
+			APIURLs:           APIURLs{REST: srv.URL + "/api/v3/"},
+			Token:             "test-token",
+			EnabledToolsets:   []string{"issues"},
+			DryRun:            dryRun,
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+		return ghServer
+	}
+	callTool := func(ghServer *gitHubServer, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = arguments
+		return ghServer.tools[name].Handler(context.Background(), request)
+	}
+	comment := map[string]any{"owner": "octocat", "repo": "hello-world", "issue_number": float64(42), "body": "Fixed in #43"}
+
+	t.Run("dry_run argument", func(t *testing.T) {
+		ghServer := newServer(false)
+		assert.Contains(t, ghServer.tools["add_issue_comment"].Tool.InputSchema.Properties, dryRunArgument)
+		assert.NotContains(t, ghServer.tools["get_issue"].Tool.InputSchema.Properties, dryRunArgument, "read tools don't change anything")
+
+		arguments := map[string]any{dryRunArgument: true}
+		for name, value := range comment {
+			arguments[name] = value
+		}
+		before := len(requests())
+		result, err := callTool(ghServer, "add_issue_comment", arguments)
+		require.NoError(t, err)
+		require.False(t, result.IsError, resultText(result))
+		assert.Len(t, requests(), before, "nothing is sent")
+
+		var dryRun dryRunResult
+		require.NoError(t, json.Unmarshal([]byte(resultText(result)), &dryRun))
+		assert.True(t, dryRun.DryRun)
+		require.Len(t, dryRun.Requests, 1)
+		assert.Equal(t, http.MethodPost, dryRun.Requests[0].Method)
+		assert.Equal(t, srv.URL+"/api/v3/repos/octocat/hello-world/issues/42/comments", dryRun.Requests[0].URL)
+		assert.Equal(t, map[string]any{"body": "Fixed in #43"}, dryRun.Requests[0].Body)
+
+		result, err = callTool(ghServer, "add_issue_comment", comment)
+		require.NoError(t, err)
+		require.False(t, result.IsError, resultText(result))
+		assert.Contains(t, requests(), "POST /api/v3/repos/octocat/hello-world/issues/42/comments")
+	})
+
+	t.Run("server-wide", func(t *testing.T) {
+		ghServer := newServer(true)
+		assert.NotContains(t, ghServer.tools["add_issue_comment"].Tool.InputSchema.Properties, dryRunArgument)
+
+		before := len(requests())
+		result, err := callTool(ghServer, "add_issue_comment", comment)
+		require.NoError(t, err)
+		assert.Contains(t, resultText(result), `"dry_run":true`)
+		assert.Len(t, requests(), before, "nothing is sent")
+	})
+}
+
+func Test_newGitHubServer_dryRunSequence(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/octocat/hello-world/git/ref/heads/main":
+			_, _ = io.WriteString(w, `{"ref":"refs/heads/main","object":{"sha":"base-commit"}}`)
+		case "/api/v3/repos/octocat/hello-world/git/commits/base-commit":
+			_, _ = io.WriteString(w, `{"sha":"base-commit","tree":{"sha":"base-tree"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ghServer, err := newGitHubServer(MCPServerConfig{
+		Version:           "test",
+		APIURLs:           APIURLs{REST: srv.URL + "/api/v3/"},
+		Token:             "test-token",
+		EnabledToolsets:   []string{"repos"},
+		DryRun:            true,
+		Translator:        translations.NullTranslationHelper,
+		ContentWindowSize: 5000,
+	})
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "push_files"
+	request.Params.Arguments = map[string]any{
+		"owner":   "octocat",
+		"repo":    "hello-world",
+		"branch":  "main",
+		"message": "Update docs",
+		"files":   []any{map[string]any{"path": "README.md", "content": "# Hello"}},
+	}
+	result, err := ghServer.tools["push_files"].Handler(context.Background(), request)
+	require.NoError(t, err)
+
+	var dryRun dryRunResult
+	require.NoError(t, json.Unmarshal([]byte(resultText(result)), &dryRun))
+	var described []string
+	for _, request := range dryRun.Requests {
+		described = append(described, request.Method+" "+strings.TrimPrefix(request.URL, srv.URL))
+	}
+	assert.Equal(t, []string{
+		"POST /api/v3/repos/octocat/hello-world/git/trees",
+		"POST /api/v3/repos/octocat/hello-world/git/commits",
+		"PATCH /api/v3/repos/octocat/hello-world/git/refs/heads/main",
+	}, described, "every change of a tool making several is described")
+	for _, request := range sent {
+		assert.True(t, strings.HasPrefix(request, "GET "), "only reads are sent, not %s", request)
+	}
+}
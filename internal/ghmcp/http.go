@@ -0,0 +1,291 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/audit"
+	"github.com/github/github-mcp-server/internal/tracing"
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultHTTPEndpointPath = "/mcp"
+	httpShutdownTimeout     = 10 * time.Second
+)
+
+type HTTPServerConfig struct {
+	// Version of the server
+	Version string
+
+	// GitHub Host to target for API requests (e.g. github.com or github.enterprise.com)
+	Host string
+
+	// APIURLs overrides the API endpoints derived from Host
+	APIURLs APIURLs
+
+	// Network configures how connections to GitHub are made
+	Network NetworkConfig
+
+	// EnabledToolsets is a list of toolsets to enable
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
+	EnabledToolsets []string
+
+	// EnabledTools and ExcludedTools are glob patterns, e.g. "delete_*", of the
+	// tools to offer from the enabled toolsets and of the tools never to offer
+	EnabledTools  []string
+	ExcludedTools []string
+
+	// AllowedRepositories restricts all tools to these owners and "owner/repo"
+	// patterns, see github.NewRepositoryPolicy. Empty allows everything the token can access.
+	AllowedRepositories []string
+
+	// Lockdown withholds content written by users without push access to the
+	// repository, except for the LockdownTrustedUsers, see github.Lockdown
+	Lockdown             bool
+	LockdownTrustedUsers []string
+
+	// SanitizeRules remove hidden content, such as HTML comments, from the
+	// user-authored text of tool results, see sanitize.NewSanitizer
+	SanitizeRules []string
+
+	// ConfirmTools and ConfirmDestructiveTools select the write tools that ask
+	// the user for confirmation before they run
+	ConfirmTools            []string
+	ConfirmDestructiveTools bool
+
+	// Whether to enable dynamic toolsets
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
+	DynamicToolsets bool
+
+	// ReadOnly indicates if we should only register read-only tools
+	ReadOnly bool
+
+	// DryRun makes every write tool call a dry run
+	DryRun bool
+
+	// ExportTranslations indicates if we should export translations
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#i18n--overriding-descriptions
+	ExportTranslations bool
+
+	// TranslationOverrides replace tool descriptions and other translated text,
+	// keyed by translation key
+	TranslationOverrides map[string]string
+
+	// Path to the log file if not stderr
+	LogFilePath string
+
+	// Content window size
+	ContentWindowSize int
+
+	// Tracing configures the export of OpenTelemetry traces, disabled when no
+	// endpoint is set
+	Tracing tracing.Config
+
+	// MetricsAddress is the TCP address to serve Prometheus metrics on, metrics
+	// are disabled when empty
+	MetricsAddress string
+
+	// AuditLogPath is the file to append a JSON record of every write tool call
+	// to, auditing is disabled when empty
+	AuditLogPath string
+
+	// RedactPatterns are regular expressions matching secrets to redact from
+	// logs and the audit log, in addition to the built-in detectors
+	RedactPatterns []string
+
+	// ShutdownGracePeriod is how long tool calls in flight get to finish on
+	// shutdown before they are cancelled, defaults to 30 seconds
+	ShutdownGracePeriod time.Duration
+
+	// ToolTimeout, ToolTimeouts and MaxConcurrentRequests bound tool calls and
+	// the GitHub API requests they make
+	ToolTimeout           time.Duration
+	ToolTimeouts          map[string]time.Duration
+	MaxConcurrentRequests int
+
+	// ListenAddress is the TCP address the server listens on, e.g. "localhost:8082"
+	ListenAddress string
+
+	// EndpointPath is the path the MCP endpoint is served on, defaults to "/mcp"
+	EndpointPath string
+}
+
+// RunHTTPServer serves the MCP streamable HTTP transport. There is no server-wide
+// token: every request must authenticate with its own GitHub token in the
+// Authorization header, which is used for all API calls made on its behalf.
+func RunHTTPServer(cfg HTTPServerConfig) error {
+	// Create app context
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	t, dumpTranslations := translations.TranslationHelperWithOverrides(cfg.TranslationOverrides)
+
+	redactor, err := mcplog.NewRedactor(cfg.RedactPatterns)
+	if err != nil {
+		return err
+	}
+	logger, _, err := newLogger(cfg.LogFilePath, redactor)
+	if err != nil {
+		return err
+	}
+
+	tracer, err := newTracer(cfg.Tracing, cfg.Network, cfg.Version, logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracer(tracer, logger)
+
+	var registry *prometheus.Registry
+	if cfg.MetricsAddress != "" {
+		registry = prometheus.NewRegistry()
+		metricsServer, err := startMetricsServer(cfg.MetricsAddress, registry, logger)
+		if err != nil {
+			return err
+		}
+		defer stopMetricsServer(metricsServer, logger)
+	}
+
+	var auditLog *audit.Log
+	if cfg.AuditLogPath != "" {
+		auditLog, err = audit.Open(cfg.AuditLogPath)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = auditLog.Close() }()
+	}
+
+	ghServer, err := newGitHubServer(MCPServerConfig{
+		Version:                 cfg.Version,
+		Host:                    cfg.Host,
+		APIURLs:                 cfg.APIURLs,
+		Network:                 cfg.Network,
+		EnabledToolsets:         cfg.EnabledToolsets,
+		EnabledTools:            cfg.EnabledTools,
+		ExcludedTools:           cfg.ExcludedTools,
+		AllowedRepositories:     cfg.AllowedRepositories,
+		Lockdown:                cfg.Lockdown,
+		LockdownTrustedUsers:    cfg.LockdownTrustedUsers,
+		SanitizeRules:           cfg.SanitizeRules,
+		ConfirmTools:            cfg.ConfirmTools,
+		ConfirmDestructiveTools: cfg.ConfirmDestructiveTools,
+		DynamicToolsets:         cfg.DynamicToolsets,
+		ReadOnly:                cfg.ReadOnly,
+		DryRun:                  cfg.DryRun,
+		Translator:              t,
+		ContentWindowSize:       cfg.ContentWindowSize,
+		Tracer:                  tracer,
+		Metrics:                 registry,
+		AuditLog:                auditLog,
+		Redactor:                redactor,
+		ToolTimeout:             cfg.ToolTimeout,
+		ToolTimeouts:            cfg.ToolTimeouts,
+		MaxConcurrentRequests:   cfg.MaxConcurrentRequests,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "address", cfg.ListenAddress, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly, "dryRun", cfg.DryRun)
+
+	if cfg.ExportTranslations {
+		// Once server is initialized, all translations are loaded
+		dumpTranslations()
+	}
+
+	endpointPath := cfg.EndpointPath
+	if endpointPath == "" {
+		endpointPath = defaultHTTPEndpointPath
+	}
+
+	streamableServer := server.NewStreamableHTTPServer(ghServer.mcpServer,
+		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			// enable GitHub errors in the context
+			ctx = ghErrors.ContextWithGitHubErrors(ctx)
+			ctx = contextWithTraceparentHeader(ctx, r)
+			if token, ok := parseAuthorizationHeader(r.Header.Get("Authorization")); ok {
+				ctx = ContextWithToken(ctx, token)
+			}
+			return ctx
+		}),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(endpointPath, requireAuthorization(streamableServer))
+
+	httpServer := &http.Server{
+		Addr:              cfg.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Start listening for requests
+	errC := make(chan error, 1)
+	go func() {
+		errC <- httpServer.ListenAndServe()
+	}()
+
+	// Output github-mcp-server string
+	_, _ = fmt.Fprintf(os.Stderr, "GitHub MCP Server running on http://%s%s\n", cfg.ListenAddress, endpointPath)
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down server", "signal", "context done")
+		// A second signal exits right away
+		stop()
+		// Requests keep being served while draining, so that new tool calls
+		// are rejected with an error rather than a connection failure
+		ghServer.calls.drain(shutdownGracePeriod(cfg.ShutdownGracePeriod), logger)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down server: %w", err)
+		}
+	case err := <-errC:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error running server", "error", err)
+			return fmt.Errorf("error running server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// requireAuthorization rejects requests that do not carry a GitHub token, so that
+// unauthenticated clients fail at the transport rather than on every tool call.
+func requireAuthorization(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := parseAuthorizationHeader(r.Header.Get("Authorization")); !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="github-mcp-server"`)
+			http.Error(w, "missing or malformed Authorization header, expected a GitHub token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseAuthorizationHeader extracts the token from an Authorization header using
+// either the "Bearer" or the GitHub-style "token" scheme.
+func parseAuthorizationHeader(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found {
+		return "", false
+	}
+	if !strings.EqualFold(scheme, "bearer") && !strings.EqualFold(scheme, "token") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
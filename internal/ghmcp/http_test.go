@@ -0,0 +1,57 @@
+package ghmcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuthorizationHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		expectedToken string
+		expectedOK    bool
+	}{
+		{name: "bearer scheme", header: "Bearer ghp_abc", expectedToken: "ghp_abc", expectedOK: true},
+		{name: "token scheme", header: "token ghp_abc", expectedToken: "ghp_abc", expectedOK: true},
+		{name: "scheme is case insensitive", header: "bearer ghp_abc", expectedToken: "ghp_abc", expectedOK: true},
+		{name: "empty header", header: "", expectedOK: false},
+		{name: "missing token", header: "Bearer ", expectedOK: false},
+		{name: "basic scheme", header: "Basic dXNlcjpwYXNz", expectedOK: false},
+		{name: "no scheme", header: "ghp_abc", expectedOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token, ok := parseAuthorizationHeader(tc.header)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedToken, token)
+		})
+	}
+}
+
+func TestRequireAuthorization(t *testing.T) {
+	handler := requireAuthorization(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("rejects requests without a token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+	})
+
+	t.Run("passes through requests with a token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer ghp_abc")
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
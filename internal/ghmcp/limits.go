@@ -0,0 +1,122 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// timeoutMiddleware bounds the duration of tool calls, applying the timeout
+// configured for the tool or defaultTimeout to the handler context. A zero
+// timeout leaves calls unbounded.
+func timeoutMiddleware(defaultTimeout time.Duration, toolTimeouts map[string]time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			timeout, ok := toolTimeouts[request.Params.Name]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			result, err := next(ctx, request)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// Whatever the handler made of the cancelled requests, the reason is the timeout
+				return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s, try narrowing the request", request.Params.Name, timeout)), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// concurrencyLimitTransport bounds the number of GitHub API requests in flight
+// across all tool calls, queueing the requests beyond the limit. A request
+// occupies its slot until its response headers arrive, so that slow consumers
+// of response bodies don't hold up other requests.
+type concurrencyLimitTransport struct {
+	transport http.RoundTripper
+	slots     chan struct{}
+	metrics   *limiterMetrics
+}
+
+// limiterMetrics are the metrics recorded for the queue of GitHub API requests.
+type limiterMetrics struct {
+	inFlight  prometheus.Gauge
+	queued    prometheus.Gauge
+	queueWait prometheus.Histogram
+}
+
+// newConcurrencyLimiter returns a function wrapping transports so that they share
+// a limit of maxRequests requests in flight. When maxRequests isn't positive,
+// transports are returned unchanged.
+func newConcurrencyLimiter(maxRequests int, registry *prometheus.Registry) (func(http.RoundTripper) http.RoundTripper, error) {
+	if maxRequests <= 0 {
+		return func(transport http.RoundTripper) http.RoundTripper { return transport }, nil
+	}
+
+	slots := make(chan struct{}, maxRequests)
+	var m *limiterMetrics
+	if registry != nil {
+		m = &limiterMetrics{}
+		var err error
+		if m.inFlight, err = registerMetric(registry, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "github_mcp_github_requests_in_flight",
+			Help: "GitHub API requests waiting for a response.",
+		})); err != nil {
+			return nil, err
+		}
+		if m.queued, err = registerMetric(registry, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "github_mcp_github_requests_queued",
+			Help: "GitHub API requests waiting for the concurrency limit.",
+		})); err != nil {
+			return nil, err
+		}
+		if m.queueWait, err = registerMetric(registry, prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "github_mcp_github_request_queue_wait_seconds",
+			Help:    "Time GitHub API requests waited for the concurrency limit in seconds.",
+			Buckets: latencyBuckets,
+		})); err != nil {
+			return nil, err
+		}
+	}
+	return func(transport http.RoundTripper) http.RoundTripper {
+		return &concurrencyLimitTransport{transport: transport, slots: slots, metrics: m}
+	}, nil
+}
+
+func (t *concurrencyLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if t.metrics != nil {
+		t.metrics.queued.Inc()
+	}
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		if t.metrics != nil {
+			t.metrics.queued.Dec()
+		}
+		return nil, fmt.Errorf("waiting for a GitHub API request slot: %w", req.Context().Err())
+	}
+	if t.metrics != nil {
+		t.metrics.queued.Dec()
+		t.metrics.queueWait.Observe(time.Since(start).Seconds())
+		t.metrics.inFlight.Inc()
+	}
+
+	defer func() {
+		<-t.slots
+		if t.metrics != nil {
+			t.metrics.inFlight.Dec()
+		}
+	}()
+	return t.transport.RoundTrip(req)
+}
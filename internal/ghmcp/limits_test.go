@@ -0,0 +1,107 @@
+package ghmcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_timeoutMiddleware(t *testing.T) {
+	middleware := timeoutMiddleware(time.Hour, map[string]time.Duration{"get_job_logs": 10 * time.Millisecond, "search_code": 0})
+	call := func(name string) (*mcp.CallToolResult, time.Time) {
+		var deadline time.Time
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		result, err := middleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			deadline, _ = ctx.Deadline()
+			if name == "get_job_logs" {
+				<-ctx.Done()
+				return mcp.NewToolResultError("failed to get job logs: " + ctx.Err().Error()), nil
+			}
+			return mcp.NewToolResultText("done"), nil
+		})(context.Background(), request)
+		require.NoError(t, err)
+		return result, deadline
+	}
+
+	result, deadline := call("get_me")
+	assert.False(t, result.IsError)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute, "the default timeout applies")
+
+	result, deadline = call("search_code")
+	assert.False(t, result.IsError)
+	assert.True(t, deadline.IsZero(), "a zero timeout leaves the call unbounded")
+
+	result, _ = call("get_job_logs")
+	require.True(t, result.IsError)
+	assert.Equal(t, "get_job_logs timed out after 10ms, try narrowing the request", result.Content[0].(mcp.TextContent).Text)
+}
+
+// blockingTransport holds requests until they are released.
+type blockingTransport struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *blockingTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
+	t.started <- struct{}{}
+	<-t.release
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func Test_concurrencyLimitTransport(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	backend := &blockingTransport{started: make(chan struct{}, 3), release: make(chan struct{})}
+	limit, err := newConcurrencyLimiter(1, registry)
+	require.NoError(t, err)
+	rest, graphQL := limit(backend), limit(backend)
+
+	roundTrip := func(transport http.RoundTripper, ctx context.Context) chan error {
+		errs := make(chan error, 1)
+		go func() {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+			_, err := transport.RoundTrip(req)
+			errs <- err
+		}()
+		return errs
+	}
+	output := func() string {
+		return gatherMetrics(t, registry)
+	}
+
+	first := roundTrip(rest, context.Background())
+	<-backend.started
+	second := roundTrip(graphQL, context.Background())
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelled := roundTrip(rest, cancelledCtx)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(output(), "github_mcp_github_requests_queued 2")
+	}, time.Second, time.Millisecond, "transports share the limit")
+	assert.Contains(t, output(), "github_mcp_github_requests_in_flight 1")
+
+	cancel()
+	assert.ErrorIs(t, <-cancelled, context.Canceled, "queued requests give up with their context")
+
+	backend.release <- struct{}{}
+	require.NoError(t, <-first)
+	<-backend.started
+	backend.release <- struct{}{}
+	require.NoError(t, <-second)
+
+	assert.Contains(t, output(), "github_mcp_github_requests_in_flight 0")
+	assert.Contains(t, output(), "github_mcp_github_requests_queued 0")
+	assert.Contains(t, output(), "github_mcp_github_request_queue_wait_seconds_count 2")
+
+	unlimited := &blockingTransport{}
+	noLimit, err := newConcurrencyLimiter(0, nil)
+	require.NoError(t, err)
+	assert.Same(t, unlimited, noLimit(unlimited))
+}
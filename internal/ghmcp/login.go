@@ -0,0 +1,157 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/auth"
+)
+
+type LoginConfig struct {
+	// GitHub Host to log in to (e.g. github.com or github.enterprise.com)
+	Host string
+
+	// ClientID of the OAuth app used for the device flow
+	ClientID string
+
+	// Scopes to request for the token
+	Scopes []string
+
+	// Network configures how connections to GitHub are made
+	Network NetworkConfig
+
+	// TokenCachePath is where the token is stored, defaults to auth.DefaultTokenCachePath
+	TokenCachePath string
+
+	// Output receives the instructions for the user
+	Output io.Writer
+}
+
+// RunLogin obtains a token through the OAuth device flow and stores it in the
+// token cache, from where the stdio server picks it up when no token is configured.
+func RunLogin(cfg LoginConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.ClientID == "" {
+		return errors.New("an OAuth client ID is required, set --client-id or GITHUB_CLIENT_ID")
+	}
+
+	transport, err := newBaseTransport(cfg.Network)
+	if err != nil {
+		return fmt.Errorf("failed to configure network: %w", err)
+	}
+
+	apiHost, err := parseAPIHost(cfg.Host, APIURLs{}, transport)
+	if err != nil {
+		return fmt.Errorf("failed to parse API host: %w", err)
+	}
+
+	cache, err := newTokenCache(cfg.TokenCachePath)
+	if err != nil {
+		return err
+	}
+
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	flow := &auth.DeviceFlow{
+		WebURL:     apiHost.webURL,
+		ClientID:   cfg.ClientID,
+		Scopes:     cfg.Scopes,
+		HTTPClient: &http.Client{Transport: transport},
+	}
+
+	code, err := flow.RequestCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(output, "First copy your one-time code: %s\n", code.UserCode)
+	_, _ = fmt.Fprintf(output, "Then open %s in your browser and enter the code to authorize github-mcp-server.\n", code.VerificationURI)
+	_, _ = fmt.Fprintf(output, "Waiting for authorization...\n")
+
+	token, err := flow.PollToken(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Store(apiHost.webURL.Host, &auth.CachedToken{
+		Token:     token.Token,
+		Scopes:    token.Scope,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(output, "Logged in to %s with scopes %q. Token stored in %s\n", apiHost.webURL.Host, strings.ReplaceAll(token.Scope, ",", ", "), cache.Path())
+	return nil
+}
+
+// CachedToken returns the token stored by RunLogin for host, or
+// auth.ErrNoCachedToken if the user has not logged in to that host.
+func CachedToken(host string, tokenCachePath string) (string, error) {
+	key, err := tokenCacheKey(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse API host: %w", err)
+	}
+
+	cache, err := newTokenCache(tokenCachePath)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := cache.Load(key)
+	if err != nil {
+		return "", err
+	}
+	return token.Token, nil
+}
+
+// tokenCacheKey returns the host of the web URL parseAPIHost determines for
+// host, under which RunLogin stores tokens. Unlike parseAPIHost, it makes no
+// requests, as the web URL doesn't depend on how GHES is set up.
+func tokenCacheKey(host string) (string, error) {
+	if host == "" {
+		return "github.com", nil
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", fmt.Errorf("could not parse host as URL: %s", host)
+	}
+	if u.Scheme == "" {
+		return "", fmt.Errorf("host must have a scheme (http or https): %s", host)
+	}
+
+	switch {
+	case strings.HasSuffix(u.Hostname(), "github.com"):
+		return "github.com", nil
+	case strings.HasSuffix(u.Hostname(), "ghe.com"):
+		return u.Hostname(), nil
+	default:
+		return u.Host, nil
+	}
+}
+
+func newTokenCache(path string) (*auth.TokenCache, error) {
+	if path == "" {
+		defaultPath, err := auth.DefaultTokenCachePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return auth.NewTokenCache(path), nil
+}
@@ -0,0 +1,30 @@
+package ghmcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tokenCacheKey(t *testing.T) {
+	for host, expected := range map[string]string{
+		"":                                "github.com",
+		"https://github.com":              "github.com",
+		"https://api.github.com":          "github.com",
+		"https://octo.ghe.com":            "octo.ghe.com",
+		"https://github.example.com:8443": "github.example.com:8443",
+	} {
+		key, err := tokenCacheKey(host)
+		require.NoError(t, err)
+		assert.Equal(t, expected, key, host)
+
+		// The key must match the host RunLogin stores tokens under
+		apiHost, err := parseAPIHost(host, APIURLs{Upload: "https://uploads.example.com/", Raw: "https://raw.example.com/"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, apiHost.webURL.Host, key, host)
+	}
+
+	_, err := tokenCacheKey("github.example.com")
+	assert.EqualError(t, err, "host must have a scheme (http or https): github.example.com")
+}
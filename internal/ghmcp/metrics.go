@@ -0,0 +1,200 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsShutdownTimeout = 5 * time.Second
+
+// latencyBuckets are the upper bounds of histogram buckets for latencies in
+// seconds, from fast cached responses to slow paginated searches.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// registerMetric registers collector with registry, or returns the collector
+// registered before with the same description, so that servers created
+// repeatedly with the same registry share their metrics.
+func registerMetric[T prometheus.Collector](registry prometheus.Registerer, collector T) (T, error) {
+	err := registry.Register(collector)
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to register metric: %w", err)
+	}
+	return collector, nil
+}
+
+// serverMetrics are the metrics recorded for tool calls.
+type serverMetrics struct {
+	toolCalls          *prometheus.CounterVec
+	toolCallDuration   *prometheus.HistogramVec
+	githubErrors       *prometheus.CounterVec
+	rateLimitLimit     *prometheus.GaugeVec
+	rateLimitRemaining *prometheus.GaugeVec
+	rateLimitReset     *prometheus.GaugeVec
+}
+
+func newServerMetrics(registry prometheus.Registerer) (*serverMetrics, error) {
+	var m serverMetrics
+	var err error
+	if m.toolCalls, err = registerMetric(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_mcp_tool_calls_total",
+		Help: "Tool calls by tool and outcome, which is error when the tool reported an error.",
+	}, []string{"tool", "outcome"})); err != nil {
+		return nil, err
+	}
+	if m.toolCallDuration, err = registerMetric(registry, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "github_mcp_tool_call_duration_seconds",
+		Help:    "Duration of tool calls in seconds.",
+		Buckets: latencyBuckets,
+	}, []string{"tool"})); err != nil {
+		return nil, err
+	}
+	if m.githubErrors, err = registerMetric(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_mcp_github_errors_total",
+		Help: "GitHub API errors reported by tool calls, by tool, API and HTTP status.",
+	}, []string{"tool", "api", "status"})); err != nil {
+		return nil, err
+	}
+	if m.rateLimitLimit, err = registerMetric(registry, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "github_mcp_rate_limit_limit",
+		Help: "Rate limit of the most recent GitHub API response, by resource.",
+	}, []string{"resource"})); err != nil {
+		return nil, err
+	}
+	if m.rateLimitRemaining, err = registerMetric(registry, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "github_mcp_rate_limit_remaining",
+		Help: "Remaining rate limit of the most recent GitHub API response, by resource.",
+	}, []string{"resource"})); err != nil {
+		return nil, err
+	}
+	if m.rateLimitReset, err = registerMetric(registry, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "github_mcp_rate_limit_reset_timestamp_seconds",
+		Help: "Unix time at which the rate limit of the most recent GitHub API response resets, by resource.",
+	}, []string{"resource"})); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// metricsMiddleware records the outcome, duration, GitHub errors and rate limit
+// budget of every tool call.
+func metricsMiddleware(registry *prometheus.Registry) (server.ToolHandlerMiddleware, error) {
+	if registry == nil {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc { return next }, nil
+	}
+	m, err := newServerMetrics(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := request.Params.Name
+
+			// GitHub errors are collected in a holder shared by the calls of a
+			// session, so only the errors added during this call are counted
+			apiErrs, _ := ghErrors.GetGitHubAPIErrors(ctx)
+			gqlErrs, _ := ghErrors.GetGitHubGraphQLErrors(ctx)
+			apiErrsBefore, gqlErrsBefore := len(apiErrs), len(gqlErrs)
+
+			start := time.Now()
+			result, err := next(ctx, request)
+			m.toolCallDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+
+			outcome := "success"
+			if err != nil || (result != nil && result.IsError) {
+				outcome = "error"
+			}
+			m.toolCalls.WithLabelValues(tool, outcome).Inc()
+
+			if apiErrs, _ := ghErrors.GetGitHubAPIErrors(ctx); len(apiErrs) > apiErrsBefore {
+				for _, apiErr := range apiErrs[apiErrsBefore:] {
+					m.githubErrors.WithLabelValues(tool, "rest", apiErrorStatus(apiErr)).Inc()
+				}
+			}
+			if gqlErrs, _ := ghErrors.GetGitHubGraphQLErrors(ctx); len(gqlErrs) > gqlErrsBefore {
+				for range gqlErrs[gqlErrsBefore:] {
+					m.githubErrors.WithLabelValues(tool, "graphql", "none").Inc()
+				}
+			}
+
+			if recorder := rateLimitRecorderFromContext(ctx); recorder != nil {
+				if limit, ok := recorder.get(); ok {
+					m.recordRateLimit(limit)
+				}
+			}
+			return result, err
+		}
+	}, nil
+}
+
+func (m *serverMetrics) recordRateLimit(limit *rateLimit) {
+	resource := limit.Resource
+	if resource == "" {
+		resource = "unknown"
+	}
+	m.rateLimitLimit.WithLabelValues(resource).Set(float64(limit.Limit))
+	m.rateLimitRemaining.WithLabelValues(resource).Set(float64(limit.Remaining))
+	if reset, err := time.Parse(time.RFC3339, limit.Reset); err == nil {
+		m.rateLimitReset.WithLabelValues(resource).Set(float64(reset.Unix()))
+	}
+}
+
+// apiErrorStatus returns the HTTP status of a REST API error, or "none" when the
+// request failed without a response.
+func apiErrorStatus(err *ghErrors.GitHubAPIError) string {
+	if err.Response == nil || err.Response.Response == nil {
+		return "none"
+	}
+	return strconv.Itoa(err.Response.StatusCode)
+}
+
+// startMetricsServer serves the metrics in registry on /metrics at address. It
+// listens before returning, so that a port that is already in use fails startup.
+func startMetricsServer(address string, registry *prometheus.Registry, logger *slog.Logger) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}))
+	metricsServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := metricsServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error serving metrics", "error", err)
+		}
+	}()
+	logger.Info("serving metrics", "address", listener.Addr().String())
+	return metricsServer, nil
+}
+
+// stopMetricsServer shuts down a server started by startMetricsServer.
+func stopMetricsServer(metricsServer *http.Server, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		logger.Warn("failed to shut down metrics server", "error", err)
+	}
+}
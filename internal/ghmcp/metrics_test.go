@@ -0,0 +1,100 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_metricsMiddleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	middleware, err := metricsMiddleware(registry)
+	require.NoError(t, err)
+
+	getIssue := rateLimitMiddleware(middleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		rateLimitRecorderFromContext(ctx).record(http.Header{
+			"X-Ratelimit-Limit":     {"5000"},
+			"X-Ratelimit-Remaining": {"4321"},
+			"X-Ratelimit-Resource":  {"core"},
+			"X-Ratelimit-Reset":     {"1735732800"},
+		})
+		resp := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get issue", resp, errors.New("not found")), nil
+	}))
+	listIssues := middleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to list issues", errors.New("timeout")), nil
+	})
+	getMe := middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("octocat"), nil
+	})
+
+	// Calls of a session share the holder of GitHub errors
+	sessionCtx := ghErrors.ContextWithGitHubErrors(context.Background())
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), name string) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		_, err := handler(sessionCtx, request)
+		require.NoError(t, err)
+	}
+	call(getMe, "get_me")
+	call(getMe, "get_me")
+	call(getIssue, "get_issue")
+	call(listIssues, "list_issues")
+
+	output := gatherMetrics(t, registry)
+
+	assert.Contains(t, output, `github_mcp_tool_calls_total{outcome="success",tool="get_me"} 2`)
+	assert.Contains(t, output, `github_mcp_tool_calls_total{outcome="error",tool="get_issue"} 1`)
+	assert.Contains(t, output, `github_mcp_tool_call_duration_seconds_count{tool="get_me"} 2`)
+	assert.Contains(t, output, `github_mcp_tool_call_duration_seconds_bucket{tool="get_me",le="+Inf"} 2`)
+	assert.Contains(t, output, `github_mcp_github_errors_total{api="rest",status="404",tool="get_issue"} 1`)
+	assert.Contains(t, output, `github_mcp_github_errors_total{api="graphql",status="none",tool="list_issues"} 1`)
+	assert.NotContains(t, output, `github_mcp_github_errors_total{api="rest",status="404",tool="list_issues"}`, "errors of earlier calls must not be counted again")
+	assert.Contains(t, output, `github_mcp_rate_limit_remaining{resource="core"} 4321`)
+	assert.Contains(t, output, `github_mcp_rate_limit_limit{resource="core"} 5000`)
+	assert.Contains(t, output, `github_mcp_rate_limit_reset_timestamp_seconds{resource="core"} 1.7357328e+09`)
+}
+
+func Test_metricsMiddleware_Registration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first, err := metricsMiddleware(registry)
+	require.NoError(t, err)
+	second, err := metricsMiddleware(registry)
+	require.NoError(t, err, "servers created again with the same registry share their metrics")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_me"
+	for _, middleware := range []func(next server.ToolHandlerFunc) server.ToolHandlerFunc{first, second} {
+		_, err := middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("octocat"), nil
+		})(context.Background(), request)
+		require.NoError(t, err)
+	}
+	assert.Contains(t, gatherMetrics(t, registry), `github_mcp_tool_calls_total{outcome="success",tool="get_me"} 2`)
+
+	conflicting := prometheus.NewRegistry()
+	conflicting.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "github_mcp_tool_calls_total", Help: "Something else."}))
+	_, err = metricsMiddleware(conflicting)
+	assert.ErrorContains(t, err, "failed to register metric")
+}
+
+// gatherMetrics returns the metrics of registry in the text format served to
+// scrapers.
+func gatherMetrics(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
@@ -0,0 +1,241 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate limited request is retried.
+	maxRateLimitRetries = 3
+	// maxRateLimitWait bounds the wait before a retry when the request has no
+	// deadline, so that a tool call never hangs for the better part of an hour.
+	maxRateLimitWait = 2 * time.Minute
+	// secondaryRateLimitBackoff is the initial wait after a secondary rate limit
+	// response that doesn't say how long to wait, doubled on each retry.
+	// See https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api#handle-rate-limit-errors-appropriately
+	secondaryRateLimitBackoff = time.Minute
+	// rateLimitMetaKey is the key under which tool results report the remaining rate limit budget.
+	rateLimitMetaKey = "github/rate_limit"
+)
+
+// rateLimitTransport retries requests that hit the primary or secondary rate
+// limit, waiting as long as GitHub asks to, provided the wait fits within the
+// request's deadline. It also records the rate limit headers of every response
+// for the tool call the request was made for, see contextWithRateLimitRecorder.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimitTransport(transport http.RoundTripper) *rateLimitTransport {
+	return &rateLimitTransport{
+		transport: transport,
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	recorder := rateLimitRecorderFromContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if recorder != nil {
+			recorder.record(resp.Header)
+		}
+
+		if attempt == maxRateLimitRetries || !isRateLimited(resp) {
+			return resp, nil
+		}
+
+		wait := t.retryAfter(resp, attempt)
+		if !t.canWait(ctx, wait) {
+			return resp, nil
+		}
+
+		// Only requests whose body can be replayed can be retried
+		retry := req
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry = req.Clone(ctx)
+			retry.Body = body
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+		req = retry
+	}
+}
+
+// retryAfter determines how long to wait before retrying a rate limited request.
+func (t *rateLimitTransport) retryAfter(resp *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			// The reset time has a one second resolution, so wait an extra second
+			// to make sure the window has actually rolled over.
+			if wait := time.Unix(reset, 0).Sub(t.now()) + time.Second; wait > 0 {
+				return wait
+			}
+			return 0
+		}
+	}
+	return secondaryRateLimitBackoff << attempt
+}
+
+// canWait reports whether waiting for d still leaves time to retry the request.
+func (t *rateLimitTransport) canWait(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok {
+		return t.now().Add(d).Before(deadline)
+	}
+	return d <= maxRateLimitWait
+}
+
+// isRateLimited reports whether resp is a primary or secondary rate limit
+// response. GitHub signals both with a 403 or 429 status, which for 403 is
+// only distinguishable from a permission error by headers or the message.
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimit is the rate limit budget reported by the most recent API response.
+type rateLimit struct {
+	Resource  string `json:"resource,omitempty"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Used      int    `json:"used"`
+	Reset     string `json:"reset,omitempty"`
+}
+
+// rateLimitRecorder collects the rate limit budget of the API requests made for
+// a single tool call.
+type rateLimitRecorder struct {
+	mu   sync.Mutex
+	last *rateLimit
+}
+
+func (r *rateLimitRecorder) record(header http.Header) {
+	limits, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = limits
+}
+
+// parseRateLimit returns the rate limit budget reported in the headers of a
+// response, if any.
+func parseRateLimit(header http.Header) (*rateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return nil, false
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+	limits := &rateLimit{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		limits.Reset = time.Unix(reset, 0).UTC().Format(time.RFC3339)
+	}
+	return limits, true
+}
+
+func (r *rateLimitRecorder) get() (*rateLimit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.last != nil
+}
+
+type rateLimitRecorderCtxKey struct{}
+
+func contextWithRateLimitRecorder(ctx context.Context) (context.Context, *rateLimitRecorder) {
+	recorder := &rateLimitRecorder{}
+	return context.WithValue(ctx, rateLimitRecorderCtxKey{}, recorder), recorder
+}
+
+func rateLimitRecorderFromContext(ctx context.Context) *rateLimitRecorder {
+	recorder, _ := ctx.Value(rateLimitRecorderCtxKey{}).(*rateLimitRecorder)
+	return recorder
+}
+
+// rateLimitMiddleware reports the remaining rate limit budget in the _meta of
+// tool results, so that agents can pace themselves before they get throttled.
+func rateLimitMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, recorder := contextWithRateLimitRecorder(ctx)
+		result, err := next(ctx, request)
+		if result == nil {
+			return result, err
+		}
+		if limit, ok := recorder.get(); ok {
+			if result.Meta == nil {
+				result.Meta = &mcp.Meta{}
+			}
+			if result.Meta.AdditionalFields == nil {
+				result.Meta.AdditionalFields = map[string]any{}
+			}
+			result.Meta.AdditionalFields[rateLimitMetaKey] = limit
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,190 @@
+package ghmcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rateLimitTransport(t *testing.T) {
+	// The deadline of the request context is checked against the real clock
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name             string
+		responses        []func(w http.ResponseWriter)
+		timeout          time.Duration
+		expectedStatus   int
+		expectedRequests int
+		expectedWaits    []time.Duration
+	}{
+		{
+			name: "successful request is not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+		},
+		{
+			name: "permission error is not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = io.WriteString(w, `{"message":"Resource not accessible by integration"}`)
+				},
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedRequests: 1,
+		},
+		{
+			name: "secondary rate limit with Retry-After is retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "3")
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{3 * time.Second},
+		},
+		{
+			name: "secondary rate limit without headers backs off exponentially",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = io.WriteString(w, `{"message":"You have exceeded a secondary rate limit."}`)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{time.Minute, 2 * time.Minute},
+		},
+		{
+			name: "primary rate limit waits until the reset",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Second).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{31 * time.Second},
+		},
+		{
+			name: "wait beyond the deadline is not attempted",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "60")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+			},
+			timeout:          10 * time.Second,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedRequests: 1,
+		},
+		{
+			name: "wait beyond the maximum is not attempted without deadline",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			var bodies []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				tc.responses[requests](w)
+				requests++
+			}))
+			defer srv.Close()
+
+			var waits []time.Duration
+			transport := newRateLimitTransport(http.DefaultTransport)
+			transport.now = func() time.Time { return now }
+			transport.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, now.Add(tc.timeout))
+				defer cancel()
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader(`{"query":"test"}`))
+			require.NoError(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tc.expectedRequests, requests)
+			assert.Equal(t, tc.expectedWaits, waits)
+			for _, body := range bodies {
+				assert.Equal(t, `{"query":"test"}`, body, "request body must be replayed on retry")
+			}
+		})
+	}
+}
+
+func Test_rateLimitMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Used", "1")
+		w.Header().Set("X-RateLimit-Reset", "1735732800")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport)}
+	handler := rateLimitMiddleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &rateLimit{
+		Resource:  "core",
+		Limit:     5000,
+		Remaining: 4999,
+		Used:      1,
+		Reset:     "2025-01-01T12:00:00Z",
+	}, result.Meta.AdditionalFields[rateLimitMetaKey])
+}
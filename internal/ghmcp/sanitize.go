@@ -0,0 +1,112 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sanitizedMetaKey is the key of the changes made by sanitization in the _meta
+// of tool results.
+const sanitizedMetaKey = "github/sanitized"
+
+// sanitizedFields are the fields of JSON tool results holding user-authored
+// text, such as the bodies of issues, pull requests, comments and discussions,
+// the messages of commits and the descriptions of gists.
+var sanitizedFields = map[string]bool{
+	"body":        true,
+	"title":       true,
+	"message":     true,
+	"description": true,
+}
+
+// sanitizeMiddleware removes hidden content from the user-authored fields of
+// JSON tool results, and reports the changes in the _meta of the result.
+// Other results, such as diffs and file contents, are left as they are.
+func sanitizeMiddleware(sanitizer *sanitize.Sanitizer) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if result == nil || result.IsError {
+				return result, err
+			}
+
+			changes := sanitize.Changes{}
+			for i, content := range result.Content {
+				text, ok := content.(mcp.TextContent)
+				if !ok {
+					continue
+				}
+				sanitized, textChanges := sanitizeJSON(sanitizer, text.Text)
+				if len(textChanges) == 0 {
+					continue
+				}
+				text.Text = sanitized
+				result.Content[i] = text
+				changes.Add(textChanges)
+			}
+
+			if len(changes) > 0 {
+				if result.Meta == nil {
+					result.Meta = &mcp.Meta{}
+				}
+				if result.Meta.AdditionalFields == nil {
+					result.Meta.AdditionalFields = map[string]any{}
+				}
+				result.Meta.AdditionalFields[sanitizedMetaKey] = changes
+			}
+			return result, err
+		}
+	}
+}
+
+// sanitizeJSON sanitizes the user-authored fields of text when it is JSON.
+// Text is only re-encoded when something changed.
+func sanitizeJSON(sanitizer *sanitize.Sanitizer, text string) (string, sanitize.Changes) {
+	decoder := json.NewDecoder(bytes.NewBufferString(text))
+	// Keep IDs too large for a float64 intact
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return text, nil
+	}
+
+	changes := sanitize.Changes{}
+	value = sanitizeValue(sanitizer, "", value, changes)
+	if len(changes) == 0 {
+		return text, nil
+	}
+	sanitized, err := json.Marshal(value)
+	if err != nil {
+		return text, nil
+	}
+	return string(sanitized), changes
+}
+
+// sanitizeValue sanitizes the strings of the user-authored fields in value,
+// which is the value of the field name.
+func sanitizeValue(sanitizer *sanitize.Sanitizer, name string, value any, changes sanitize.Changes) any {
+	switch v := value.(type) {
+	case string:
+		if !sanitizedFields[name] {
+			return v
+		}
+		sanitized, stringChanges := sanitizer.Sanitize(v)
+		changes.Add(stringChanges)
+		return sanitized
+	case map[string]any:
+		for key, field := range v {
+			v[key] = sanitizeValue(sanitizer, key, field, changes)
+		}
+	case []any:
+		// Elements of lists belong to the field of the list
+		for i, element := range v {
+			v[i] = sanitizeValue(sanitizer, name, element, changes)
+		}
+	}
+	return value
+}
@@ -6,22 +6,33 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/github/github-mcp-server/internal/audit"
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/github/github-mcp-server/pkg/auth"
 	"github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/github"
 	mcplog "github.com/github/github-mcp-server/pkg/log"
 	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
 	gogithub "github.com/google/go-github/v74/github"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shurcooL/githubv4"
 )
 
@@ -32,13 +43,51 @@ type MCPServerConfig struct {
 	// GitHub Host to target for API requests (e.g. github.com or github.enterprise.com)
 	Host string
 
-	// GitHub Token to authenticate with the GitHub API
+	// APIURLs overrides the API endpoints derived from Host
+	APIURLs APIURLs
+
+	// Network configures how connections to GitHub are made
+	Network NetworkConfig
+
+	// GitHub Token to authenticate with the GitHub API. When empty, every request
+	// must carry its own token in the context, see ContextWithToken.
 	Token string
 
+	// AppID, AppPrivateKeyPath and AppInstallationID configure authentication as
+	// a GitHub App installation. When AppID is set, installation tokens are used
+	// instead of Token.
+	AppID             int64
+	AppPrivateKeyPath string
+	AppInstallationID int64
+
 	// EnabledToolsets is a list of toolsets to enable
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
 	EnabledToolsets []string
 
+	// EnabledTools and ExcludedTools are glob patterns, e.g. "delete_*", of the
+	// tools to offer from the enabled toolsets and of the tools never to offer
+	EnabledTools  []string
+	ExcludedTools []string
+
+	// AllowedRepositories restricts all tools to these owners and "owner/repo"
+	// patterns, see github.NewRepositoryPolicy. Empty allows everything the token can access.
+	AllowedRepositories []string
+
+	// Lockdown withholds content written by users without push access to the
+	// repository, except for the LockdownTrustedUsers, see github.Lockdown
+	Lockdown             bool
+	LockdownTrustedUsers []string
+
+	// SanitizeRules remove hidden content, such as HTML comments, from the
+	// user-authored text of tool results, see sanitize.NewSanitizer
+	SanitizeRules []string
+
+	// ConfirmTools are glob patterns of the write tools that ask the user for
+	// confirmation through MCP elicitation before they run. ConfirmDestructiveTools
+	// additionally does so for all tools annotated as destructive.
+	ConfirmTools            []string
+	ConfirmDestructiveTools bool
+
 	// Whether to enable dynamic toolsets
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
 	DynamicToolsets bool
@@ -46,57 +95,83 @@ type MCPServerConfig struct {
 	// ReadOnly indicates if we should only offer read-only tools
 	ReadOnly bool
 
+	// DryRun makes every write tool call a dry run, which describes the change
+	// the call would make without making it. When false, calls can still ask
+	// for a dry run with the dry_run argument.
+	DryRun bool
+
 	// Translator provides translated text for the server tooling
 	Translator translations.TranslationHelperFunc
 
 	// Content window size
 	ContentWindowSize int
+
+	// Tracer records spans for tool calls and GitHub API requests, nil disables tracing
+	Tracer *tracing.Tracer
+
+	// Metrics receives the tool call metrics, nil disables metrics
+	Metrics *prometheus.Registry
+
+	// AuditLog receives a record of every write tool call, nil disables auditing
+	AuditLog *audit.Log
+
+	// Redactor removes secrets from the audit log
+	Redactor *mcplog.Redactor
+
+	// ToolTimeout bounds the duration of tool calls, unless ToolTimeouts has a
+	// timeout for the tool. Zero leaves tool calls unbounded.
+	ToolTimeout  time.Duration
+	ToolTimeouts map[string]time.Duration
+
+	// MaxConcurrentRequests limits the GitHub API requests in flight across all
+	// tool calls, queueing the others. Zero leaves requests unlimited.
+	MaxConcurrentRequests int
 }
 
 const stdioServerLogPrefix = "stdioserver"
 
 func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
-	apiHost, err := parseAPIHost(cfg.Host)
+	ghServer, err := newGitHubServer(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse API host: %w", err)
+		return nil, err
 	}
+	return ghServer.mcpServer, nil
+}
 
-	// Construct our REST client
-	restClient := gogithub.NewClient(nil).WithAuthToken(cfg.Token)
-	restClient.UserAgent = fmt.Sprintf("github-mcp-server/%s", cfg.Version)
-	restClient.BaseURL = apiHost.baseRESTURL
-	restClient.UploadURL = apiHost.uploadURL
+// gitHubServer is the MCP server together with what it takes to reconfigure its
+// tools while it is running, see reload.
+type gitHubServer struct {
+	mcpServer      *server.MCPServer
+	appTokenSource *auth.AppInstallationTokenSource
+	getClient      github.GetClientFn
+	getGQLClient   github.GetGQLClientFn
+	getRawClient   raw.GetRawClientFn
+	logClient      *http.Client
+	clients        *clientInfos
+	policy         *github.RepositoryPolicy
+	calls          *callTracker
+
+	tokenMu sync.RWMutex
+	token   string
+
+	mu       sync.Mutex
+	cfg      MCPServerConfig
+	toolsets *toolsets.ToolsetGroup
+	tools    map[string]server.ServerTool
+}
 
-	// Construct our GraphQL client
-	// We're using NewEnterpriseClient here unconditionally as opposed to NewClient because we already
-	// did the necessary API host parsing so that github.com will return the correct URL anyway.
-	gqlHTTPClient := &http.Client{
-		Transport: &bearerAuthTransport{
-			transport: http.DefaultTransport,
-			token:     cfg.Token,
-		},
-	} // We're going to wrap the Transport later in beforeInit
-	gqlClient := githubv4.NewEnterpriseClient(apiHost.graphqlURL.String(), gqlHTTPClient)
-
-	// When a client send an initialize request, update the user agent to include the client info.
-	beforeInit := func(_ context.Context, _ any, message *mcp.InitializeRequest) {
-		userAgent := fmt.Sprintf(
-			"github-mcp-server/%s (%s/%s)",
-			cfg.Version,
-			message.Params.ClientInfo.Name,
-			message.Params.ClientInfo.Version,
-		)
-
-		restClient.UserAgent = userAgent
-
-		gqlHTTPClient.Transport = &userAgentTransport{
-			transport: gqlHTTPClient.Transport,
-			agent:     userAgent,
-		}
+func newGitHubServer(cfg MCPServerConfig) (*gitHubServer, error) {
+	baseTransport, err := newBaseTransport(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure network: %w", err)
+	}
+
+	apiHost, err := parseAPIHost(cfg.Host, cfg.APIURLs, baseTransport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API host: %w", err)
 	}
 
 	hooks := &server.Hooks{
-		OnBeforeInitialize: []server.OnBeforeInitializeFunc{beforeInit},
 		OnBeforeAny: []server.BeforeAnyHookFunc{
 			func(ctx context.Context, _ any, _ mcp.MCPMethod, _ any) {
 				// Ensure the context is cleared of any previous errors
@@ -106,6 +181,179 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		},
 	}
 
+	// The audit log records which client made a change
+	var clients *clientInfos
+	if cfg.AuditLog != nil {
+		clients = newClientInfos()
+		hooks.AddAfterInitialize(clients.afterInitialize)
+	}
+
+	sanitizer, err := sanitize.NewSanitizer(cfg.SanitizeRules)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy *github.RepositoryPolicy
+	if len(cfg.AllowedRepositories) > 0 {
+		policy, err = github.NewRepositoryPolicy(cfg.AllowedRepositories)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	enabledToolsets := resolveToolsets(cfg)
+
+	// Generate instructions based on enabled toolsets
+	instructions := github.GenerateInstructions(enabledToolsets)
+
+	metrics, err := metricsMiddleware(cfg.Metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calls are tracked outermost, so that shutdown rejects new calls before
+	// anything else happens
+	calls := newCallTracker()
+
+	serverOpts := []server.ServerOption{
+		server.WithInstructions(instructions),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(calls.middleware),
+		server.WithToolHandlerMiddleware(timeoutMiddleware(cfg.ToolTimeout, cfg.ToolTimeouts)),
+		server.WithToolHandlerMiddleware(rateLimitMiddleware),
+		server.WithToolHandlerMiddleware(tracingMiddleware(cfg.Tracer)),
+		server.WithToolHandlerMiddleware(metrics),
+	}
+	if sanitizer != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(sanitizeMiddleware(sanitizer)))
+	}
+	if policy != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(policy.ToolHandlerMiddleware))
+	}
+
+	// Lockdown looks up the permissions of authors with the client of the tool call
+	var s *gitHubServer
+	if cfg.Lockdown {
+		lockdown := github.NewLockdown(func(ctx context.Context) (*gogithub.Client, error) {
+			return s.getClient(ctx)
+		}, cfg.LockdownTrustedUsers)
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(lockdown.ToolHandlerMiddleware))
+	}
+
+	s = &gitHubServer{
+		mcpServer: github.NewServer(cfg.Version, serverOpts...),
+		clients:   clients,
+		policy:    policy,
+		calls:     calls,
+		cfg:       cfg,
+		token:     cfg.Token,
+	}
+
+	if cfg.AppID != 0 {
+		s.appTokenSource, err = newAppTokenSource(cfg.AppID, cfg.AppInstallationID, cfg.AppPrivateKeyPath, apiHost, baseTransport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// API requests are traced, share a limit of requests in flight and are
+	// retried when rate limited. REST requests additionally go through a cache of
+	// conditional requests. Mutations of tool calls in dry-run mode are held back
+	// before any of that.
+	limitConcurrency, err := newConcurrencyLimiter(cfg.MaxConcurrentRequests, cfg.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	restTransport := newDryRunTransport(newConditionalCacheTransport(
+		newRateLimitTransport(limitConcurrency(newTracingTransport(baseTransport, cfg.Tracer, "rest"))),
+		defaultResponseCacheSize,
+	))
+	gqlTransport := newDryRunTransport(newRateLimitTransport(limitConcurrency(newTracingTransport(baseTransport, cfg.Tracer, "graphql"))))
+
+	// Clients are constructed per request so that the token and user agent can
+	// vary with the session or HTTP request the tool call belongs to.
+	s.getClient = func(ctx context.Context) (*gogithub.Client, error) {
+		token, err := s.getToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		restClient := gogithub.NewClient(&http.Client{Transport: restTransport}).WithAuthToken(token)
+		restClient.UserAgent = userAgent(ctx, cfg.Version)
+		restClient.BaseURL = apiHost.baseRESTURL
+		restClient.UploadURL = apiHost.uploadURL
+		return restClient, nil
+	}
+
+	s.getGQLClient = func(ctx context.Context) (*githubv4.Client, error) {
+		token, err := s.getToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// We're using NewEnterpriseClient here unconditionally as opposed to NewClient because we already
+		// did the necessary API host parsing so that github.com will return the correct URL anyway.
+		gqlHTTPClient := &http.Client{
+			Transport: &userAgentTransport{
+				transport: &bearerAuthTransport{
+					transport: gqlTransport,
+					token:     token,
+				},
+				agent: userAgent(ctx, cfg.Version),
+			},
+		}
+		return githubv4.NewEnterpriseClient(apiHost.graphqlURL.String(), gqlHTTPClient), nil
+	}
+
+	// Workflow logs are downloaded from storage URLs GitHub redirects to, which
+	// must not receive the token, but are reached over the same network and
+	// count towards the limit of requests in flight.
+	s.logClient = &http.Client{Transport: limitConcurrency(newTracingTransport(baseTransport, cfg.Tracer, "logs"))}
+
+	s.getRawClient = func(ctx context.Context) (*raw.Client, error) {
+		client, err := s.getClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+		return raw.NewClient(client, apiHost.rawURL), nil // closing over client
+	}
+
+	tsg, dynamic, err := s.buildToolsets(cfg, enabledToolsets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register all mcp functionality with the server
+	tsg.RegisterAll(s.mcpServer)
+
+	if dynamic != nil {
+		dynamic.RegisterTools(s.mcpServer)
+	}
+
+	s.toolsets = tsg
+	s.tools = activeTools(tsg, dynamic)
+	return s, nil
+}
+
+func (s *gitHubServer) getToken(ctx context.Context) (string, error) {
+	if token, ok := tokenFromContext(ctx); ok {
+		return token, nil
+	}
+	if s.appTokenSource != nil {
+		return s.appTokenSource.Token(ctx)
+	}
+
+	s.tokenMu.RLock()
+	defer s.tokenMu.RUnlock()
+	if s.token == "" {
+		return "", fmt.Errorf("no GitHub token provided")
+	}
+	return s.token, nil
+}
+
+// resolveToolsets expands the toolsets configured in cfg into the IDs of the
+// toolsets to enable.
+func resolveToolsets(cfg MCPServerConfig) []string {
 	enabledToolsets := cfg.EnabledToolsets
 
 	// If dynamic toolsets are enabled, remove "all" from the enabled toolsets
@@ -129,47 +377,268 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		fmt.Fprintf(os.Stderr, "Invalid toolsets ignored: %s\n", strings.Join(invalidToolsets, ", "))
 	}
 
-	// Generate instructions based on enabled toolsets
-	instructions := github.GenerateInstructions(enabledToolsets)
+	return enabledToolsets
+}
 
-	ghServer := github.NewServer(cfg.Version,
-		server.WithInstructions(instructions),
-		server.WithHooks(hooks),
-	)
+// buildToolsets creates the toolsets for cfg, enabling enabledToolsets, and the
+// dynamic toolset when dynamic toolsets are enabled.
+func (s *gitHubServer) buildToolsets(cfg MCPServerConfig, enabledToolsets []string) (*toolsets.ToolsetGroup, *toolsets.Toolset, error) {
+	// Create default toolsets
+	tsg := github.DefaultToolsetGroup(cfg.ReadOnly, s.getClient, s.getGQLClient, s.getRawClient, s.logClient, cfg.Translator, cfg.ContentWindowSize)
+	err := tsg.EnableToolsets(enabledToolsets, nil)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to enable toolsets: %w", err)
+	}
+
+	if len(cfg.EnabledTools) > 0 || len(cfg.ExcludedTools) > 0 {
+		filter, err := toolsets.NewToolNameFilter(cfg.EnabledTools, cfg.ExcludedTools)
+		if err != nil {
+			return nil, nil, err
+		}
+		if unmatched := unmatchedToolPatterns(tsg, append(append([]string(nil), cfg.EnabledTools...), cfg.ExcludedTools...)); len(unmatched) > 0 {
+			fmt.Fprintf(os.Stderr, "Tool patterns matching no tool: %s\n", strings.Join(unmatched, ", "))
+		}
+		tsg.AddToolFilter(filter)
+	}
 
-	getClient := func(_ context.Context) (*gogithub.Client, error) {
-		return restClient, nil // closing over client
+	// Tools that can't be restricted to the allowed repositories aren't offered
+	if s.policy != nil {
+		tsg.AddToolFilter(s.policy.AllowsTool)
+		tsg.AddResourceTemplateMiddleware(s.policy.ResourceTemplateMiddleware)
 	}
 
-	getGQLClient := func(_ context.Context) (*githubv4.Client, error) {
-		return gqlClient, nil // closing over client
+	// The dry-run decorator goes first, so that audit records tell dry runs apart
+	tsg.AddWriteToolDecorator(dryRunDecorator(cfg.DryRun))
+
+	if cfg.AuditLog != nil {
+		tsg.AddWriteToolMiddleware(auditMiddleware(cfg.AuditLog, s.clients, cfg.Redactor))
 	}
 
-	getRawClient := func(ctx context.Context) (*raw.Client, error) {
-		client, err := getClient(ctx)
+	if len(cfg.ConfirmTools) > 0 || cfg.ConfirmDestructiveTools {
+		// The tools to confirm are those a filter excluding them leaves out
+		unconfirmed, err := toolsets.NewToolNameFilter(nil, cfg.ConfirmTools)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			return nil, nil, err
 		}
-		return raw.NewClient(client, apiHost.rawURL), nil // closing over client
+		tsg.AddWriteToolDecorator(confirmationDecorator(s.mcpServer, func(tool mcp.Tool) bool {
+			destructive := tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+			return !unconfirmed(tool) || (cfg.ConfirmDestructiveTools && destructive)
+		}))
 	}
 
-	// Create default toolsets
-	tsg := github.DefaultToolsetGroup(cfg.ReadOnly, getClient, getGQLClient, getRawClient, cfg.Translator, cfg.ContentWindowSize)
-	err = tsg.EnableToolsets(enabledToolsets, nil)
+	// Hide the tools the configured token can't use, rather than letting the model
+	// find out through failing calls.
+	grants, err := tokenGrants(cfg, s.appTokenSource, s.getClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not determine token scopes, offering all tools: %v\n", err)
+	} else if grants.Known() {
+		if hidden := hiddenTools(tsg, grants); len(hidden) > 0 {
+			fmt.Fprintf(os.Stderr, "Tools hidden because the token lacks the required scopes or permissions: %s\n", strings.Join(hidden, ", "))
+		}
+		tsg.AddToolFilter(grants.Allows)
+	}
+
+	if !cfg.DynamicToolsets {
+		return tsg, nil, nil
+	}
+	return tsg, github.InitDynamicToolset(s.mcpServer, tsg, cfg.Translator), nil
+}
+
+// unmatchedToolPatterns returns the patterns that match none of the tools of tsg,
+// most likely because of a typo.
+func unmatchedToolPatterns(tsg *toolsets.ToolsetGroup, patterns []string) []string {
+	var names []string
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			names = append(names, tool.Tool.Name)
+		}
+	}
+
+	var unmatched []string
+	for _, pattern := range patterns {
+		if !slices.ContainsFunc(names, func(name string) bool {
+			return toolsets.MatchesAnyPattern([]string{pattern}, name)
+		}) {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched
+}
 
+// activeTools returns the tools that tsg and dynamic register, keyed by name.
+func activeTools(tsg *toolsets.ToolsetGroup, dynamic *toolsets.Toolset) map[string]server.ServerTool {
+	tools := map[string]server.ServerTool{}
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetActiveTools() {
+			tools[tool.Tool.Name] = tool
+		}
+	}
+	if dynamic != nil {
+		for _, tool := range dynamic.GetActiveTools() {
+			tools[tool.Tool.Name] = tool
+		}
+	}
+	return tools
+}
+
+// toolChanges lists the names of the tools a reload added, removed or updated.
+type toolChanges struct {
+	Added   []string
+	Removed []string
+	Updated []string
+}
+
+// reload applies a changed configuration to the running server. The token,
+// enabled toolsets and tools, read-only and dry-run mode, translations and
+// content window size are taken from cfg; changing how the server connects to GitHub or switching
+// dynamic toolsets on or off requires a restart. When tools were added, removed
+// or updated, or a setting their handlers depend on changed, every tool is
+// registered again with new handlers and clients are notified that the tool
+// list changed.
+func (s *gitHubServer) reload(cfg MCPServerConfig) (toolChanges, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.cfg
+	next.Token = cfg.Token
+	next.EnabledToolsets = cfg.EnabledToolsets
+	next.EnabledTools = cfg.EnabledTools
+	next.ExcludedTools = cfg.ExcludedTools
+	next.ReadOnly = cfg.ReadOnly
+	next.DryRun = cfg.DryRun
+	next.Translator = cfg.Translator
+	next.ContentWindowSize = cfg.ContentWindowSize
+
+	s.tokenMu.Lock()
+	previousToken := s.token
+	s.token = next.Token
+	s.tokenMu.Unlock()
+
+	tsg, dynamic, err := s.buildToolsets(next, resolveToolsets(next))
 	if err != nil {
-		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+		s.tokenMu.Lock()
+		s.token = previousToken
+		s.tokenMu.Unlock()
+		return toolChanges{}, err
 	}
 
-	// Register all mcp functionality with the server
-	tsg.RegisterAll(ghServer)
+	// Toolsets enabled at runtime through dynamic tool discovery stay enabled
+	if dynamic != nil {
+		for name, toolset := range s.toolsets.Toolsets {
+			if toolset.Enabled {
+				_ = tsg.EnableToolset(name)
+			}
+		}
+	}
 
-	if cfg.DynamicToolsets {
-		dynamic := github.InitDynamicToolset(ghServer, tsg, cfg.Translator)
-		dynamic.RegisterTools(ghServer)
+	tools := activeTools(tsg, dynamic)
+
+	var changes toolChanges
+	for name, tool := range tools {
+		previous, ok := s.tools[name]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, name)
+		case !reflect.DeepEqual(previous.Tool, tool.Tool):
+			changes.Updated = append(changes.Updated, name)
+		}
+	}
+	for name := range s.tools {
+		if _, ok := tools[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Updated)
+
+	// Handlers close over these settings, and the dynamic tools over the toolset
+	// group, so they must be replaced when the settings change even if the tools
+	// themselves don't
+	unchanged := len(changes.Added) == 0 && len(changes.Removed) == 0 && len(changes.Updated) == 0
+	handlersChanged := next.ContentWindowSize != s.cfg.ContentWindowSize ||
+		next.ReadOnly != s.cfg.ReadOnly ||
+		next.DryRun != s.cfg.DryRun ||
+		next.Token != previousToken ||
+		!slices.Equal(next.EnabledTools, s.cfg.EnabledTools) ||
+		!slices.Equal(next.ExcludedTools, s.cfg.ExcludedTools)
+
+	if !unchanged || handlersChanged {
+		if len(changes.Removed) > 0 {
+			s.mcpServer.DeleteTools(changes.Removed...)
+		}
+		replaced := make([]server.ServerTool, 0, len(tools))
+		for _, tool := range tools {
+			replaced = append(replaced, tool)
+		}
+		s.mcpServer.AddTools(replaced...)
+	}
+
+	s.cfg = next
+	s.toolsets = tsg
+	s.tools = tools
+	return changes, nil
+}
+
+// newAppTokenSource returns the source of installation tokens of the GitHub App
+// installation to authenticate as, reading the private key of the app from
+// privateKeyPath.
+func newAppTokenSource(appID, installationID int64, privateKeyPath string, apiHost apiHost, transport http.RoundTripper) (*auth.AppInstallationTokenSource, error) {
+	privateKey, err := os.ReadFile(privateKeyPath) // #nosec G304 - path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	source, err := auth.NewAppInstallationTokenSource(appID, installationID, privateKey, apiHost.baseRESTURL, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub App authentication: %w", err)
+	}
+	return source, nil
+}
+
+// tokenGrants determines what the token configured on the server can access. For
+// classic and OAuth tokens that is the scopes GitHub reports for it, and for app
+// installations the permissions of the installation token. Nothing is known about
+// fine-grained personal access tokens or tokens provided per request.
+func tokenGrants(cfg MCPServerConfig, appTokenSource *auth.AppInstallationTokenSource, getClient github.GetClientFn) (github.TokenGrants, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if appTokenSource != nil {
+		permissions, err := appTokenSource.Permissions(ctx)
+		if err != nil {
+			return github.TokenGrants{}, err
+		}
+		return github.TokenGrants{Permissions: permissions}, nil
+	}
+
+	if cfg.Token == "" {
+		return github.TokenGrants{}, nil
+	}
+
+	client, err := getClient(ctx)
+	if err != nil {
+		return github.TokenGrants{}, err
 	}
+	_, resp, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return github.TokenGrants{}, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	return github.TokenGrants{Scopes: github.ParseOAuthScopes(resp.Header.Values("X-OAuth-Scopes"))}, nil
+}
 
-	return ghServer, nil
+// hiddenTools returns the sorted names of the enabled tools that grants don't allow.
+func hiddenTools(tsg *toolsets.ToolsetGroup, grants github.TokenGrants) []string {
+	var hidden []string
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetActiveTools() {
+			if !grants.Allows(tool.Tool) {
+				hidden = append(hidden, tool.Tool.Name)
+			}
+		}
+	}
+	sort.Strings(hidden)
+	return hidden
 }
 
 type StdioServerConfig struct {
@@ -179,13 +648,48 @@ type StdioServerConfig struct {
 	// GitHub Host to target for API requests (e.g. github.com or github.enterprise.com)
 	Host string
 
+	// APIURLs overrides the API endpoints derived from Host
+	APIURLs APIURLs
+
+	// Network configures how connections to GitHub are made
+	Network NetworkConfig
+
 	// GitHub Token to authenticate with the GitHub API
 	Token string
 
+	// AppID, AppPrivateKeyPath and AppInstallationID configure authentication as
+	// a GitHub App installation instead of Token
+	AppID             int64
+	AppPrivateKeyPath string
+	AppInstallationID int64
+
 	// EnabledToolsets is a list of toolsets to enable
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
 	EnabledToolsets []string
 
+	// EnabledTools and ExcludedTools are glob patterns, e.g. "delete_*", of the
+	// tools to offer from the enabled toolsets and of the tools never to offer
+	EnabledTools  []string
+	ExcludedTools []string
+
+	// AllowedRepositories restricts all tools to these owners and "owner/repo"
+	// patterns, see github.NewRepositoryPolicy. Empty allows everything the token can access.
+	AllowedRepositories []string
+
+	// Lockdown withholds content written by users without push access to the
+	// repository, except for the LockdownTrustedUsers, see github.Lockdown
+	Lockdown             bool
+	LockdownTrustedUsers []string
+
+	// SanitizeRules remove hidden content, such as HTML comments, from the
+	// user-authored text of tool results, see sanitize.NewSanitizer
+	SanitizeRules []string
+
+	// ConfirmTools and ConfirmDestructiveTools select the write tools that ask
+	// the user for confirmation before they run
+	ConfirmTools            []string
+	ConfirmDestructiveTools bool
+
 	// Whether to enable dynamic toolsets
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
 	DynamicToolsets bool
@@ -193,10 +697,17 @@ type StdioServerConfig struct {
 	// ReadOnly indicates if we should only register read-only tools
 	ReadOnly bool
 
+	// DryRun makes every write tool call a dry run
+	DryRun bool
+
 	// ExportTranslations indicates if we should export translations
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#i18n--overriding-descriptions
 	ExportTranslations bool
 
+	// TranslationOverrides replace tool descriptions and other translated text,
+	// keyed by translation key
+	TranslationOverrides map[string]string
+
 	// EnableCommandLogging indicates if we should log commands
 	EnableCommandLogging bool
 
@@ -205,6 +716,68 @@ type StdioServerConfig struct {
 
 	// Content window size
 	ContentWindowSize int
+
+	// Tracing configures the export of OpenTelemetry traces, disabled when no
+	// endpoint is set
+	Tracing tracing.Config
+
+	// MetricsAddress is the TCP address to serve Prometheus metrics on, metrics
+	// are disabled when empty
+	MetricsAddress string
+
+	// AuditLogPath is the file to append a JSON record of every write tool call
+	// to, auditing is disabled when empty
+	AuditLogPath string
+
+	// RedactPatterns are regular expressions matching secrets to redact from
+	// logs and the audit log, in addition to the built-in detectors
+	RedactPatterns []string
+
+	// ShutdownGracePeriod is how long tool calls in flight get to finish on
+	// shutdown before they are cancelled, defaults to 30 seconds
+	ShutdownGracePeriod time.Duration
+
+	// ToolTimeout, ToolTimeouts and MaxConcurrentRequests bound tool calls and
+	// the GitHub API requests they make
+	ToolTimeout           time.Duration
+	ToolTimeouts          map[string]time.Duration
+	MaxConcurrentRequests int
+
+	// Reload returns the configuration to apply when the server receives SIGHUP,
+	// see gitHubServer.reload for the settings that can change. When nil, SIGHUP
+	// is logged and otherwise ignored.
+	Reload func() (StdioServerConfig, error)
+}
+
+// mcpServerConfig returns the configuration of the MCP server run by the stdio server.
+func (cfg StdioServerConfig) mcpServerConfig(t translations.TranslationHelperFunc) MCPServerConfig {
+	return MCPServerConfig{
+		Version:                 cfg.Version,
+		Host:                    cfg.Host,
+		APIURLs:                 cfg.APIURLs,
+		Network:                 cfg.Network,
+		Token:                   cfg.Token,
+		AppID:                   cfg.AppID,
+		AppPrivateKeyPath:       cfg.AppPrivateKeyPath,
+		AppInstallationID:       cfg.AppInstallationID,
+		EnabledToolsets:         cfg.EnabledToolsets,
+		EnabledTools:            cfg.EnabledTools,
+		ExcludedTools:           cfg.ExcludedTools,
+		AllowedRepositories:     cfg.AllowedRepositories,
+		Lockdown:                cfg.Lockdown,
+		LockdownTrustedUsers:    cfg.LockdownTrustedUsers,
+		SanitizeRules:           cfg.SanitizeRules,
+		ConfirmTools:            cfg.ConfirmTools,
+		ConfirmDestructiveTools: cfg.ConfirmDestructiveTools,
+		DynamicToolsets:         cfg.DynamicToolsets,
+		ReadOnly:                cfg.ReadOnly,
+		DryRun:                  cfg.DryRun,
+		Translator:              t,
+		ContentWindowSize:       cfg.ContentWindowSize,
+		ToolTimeout:             cfg.ToolTimeout,
+		ToolTimeouts:            cfg.ToolTimeouts,
+		MaxConcurrentRequests:   cfg.MaxConcurrentRequests,
+	}
 }
 
 // RunStdioServer is not concurrent safe.
@@ -213,39 +786,49 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	t, dumpTranslations := translations.TranslationHelper()
+	t, dumpTranslations := translations.TranslationHelperWithOverrides(cfg.TranslationOverrides)
 
-	ghServer, err := NewMCPServer(MCPServerConfig{
-		Version:           cfg.Version,
-		Host:              cfg.Host,
-		Token:             cfg.Token,
-		EnabledToolsets:   cfg.EnabledToolsets,
-		DynamicToolsets:   cfg.DynamicToolsets,
-		ReadOnly:          cfg.ReadOnly,
-		Translator:        t,
-		ContentWindowSize: cfg.ContentWindowSize,
-	})
+	redactor, err := mcplog.NewRedactor(cfg.RedactPatterns)
 	if err != nil {
-		return fmt.Errorf("failed to create MCP server: %w", err)
+		return err
+	}
+	logger, logOutput, err := newLogger(cfg.LogFilePath, redactor)
+	if err != nil {
+		return err
 	}
 
-	stdioServer := server.NewStdioServer(ghServer)
+	tracer, err := newTracer(cfg.Tracing, cfg.Network, cfg.Version, logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracer(tracer, logger)
 
-	var slogHandler slog.Handler
-	var logOutput io.Writer
-	if cfg.LogFilePath != "" {
-		file, err := os.OpenFile(cfg.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	mcpServerConfig := cfg.mcpServerConfig(t)
+	mcpServerConfig.Tracer = tracer
+	mcpServerConfig.Redactor = redactor
+	if cfg.AuditLogPath != "" {
+		mcpServerConfig.AuditLog, err = audit.Open(cfg.AuditLogPath)
 		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
+			return err
 		}
-		logOutput = file
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelDebug})
-	} else {
-		logOutput = os.Stderr
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelInfo})
+		defer func() { _ = mcpServerConfig.AuditLog.Close() }()
+	}
+	if cfg.MetricsAddress != "" {
+		mcpServerConfig.Metrics = prometheus.NewRegistry()
+		metricsServer, err := startMetricsServer(cfg.MetricsAddress, mcpServerConfig.Metrics, logger)
+		if err != nil {
+			return err
+		}
+		defer stopMetricsServer(metricsServer, logger)
 	}
-	logger := slog.New(slogHandler)
-	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly)
+	ghServer, err := newGitHubServer(mcpServerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	stdioServer := server.NewStdioServer(ghServer.mcpServer)
+
+	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly, "dryRun", cfg.DryRun)
 	stdLogger := log.New(logOutput, stdioServerLogPrefix, 0)
 	stdioServer.SetErrorLogger(stdLogger)
 
@@ -254,45 +837,112 @@ func RunStdioServer(cfg StdioServerConfig) error {
 		dumpTranslations()
 	}
 
-	// Start listening for messages
+	// Start listening for messages. The session outlives the shutdown signal, so
+	// that the tool calls in flight can finish and deliver their results.
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
 	errC := make(chan error, 1)
 	go func() {
 		in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
 
 		if cfg.EnableCommandLogging {
-			loggedIO := mcplog.NewIOLogger(in, out, logger)
+			loggedIO := mcplog.NewIOLogger(in, out, logger).WithRedactor(redactor)
 			in, out = loggedIO, loggedIO
 		}
 		// enable GitHub errors in the context
-		ctx := errors.ContextWithGitHubErrors(ctx)
+		ctx := errors.ContextWithGitHubErrors(listenCtx)
 		errC <- stdioServer.Listen(ctx, in, out)
 	}()
 
 	// Output github-mcp-server string
 	_, _ = fmt.Fprintf(os.Stderr, "GitHub MCP Server running on stdio\n")
 
+	// Reload the configuration on SIGHUP, without interrupting the session
+	reloadC := make(chan os.Signal, 1)
+	signal.Notify(reloadC, syscall.SIGHUP)
+	defer signal.Stop(reloadC)
+
 	// Wait for shutdown signal
-	select {
-	case <-ctx.Done():
-		logger.Info("shutting down server", "signal", "context done")
-	case err := <-errC:
-		if err != nil {
-			logger.Error("error running server", "error", err)
-			return fmt.Errorf("error running server: %w", err)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("shutting down server", "signal", "context done")
+			// A second signal exits right away
+			stop()
+			ghServer.calls.drain(shutdownGracePeriod(cfg.ShutdownGracePeriod), logger)
+			return nil
+		case err := <-errC:
+			// Listen waits for the tool calls in flight when the client goes away
+			if err != nil {
+				logger.Error("error running server", "error", err)
+				return fmt.Errorf("error running server: %w", err)
+			}
+			return nil
+		case <-reloadC:
+			reloadStdioServer(ghServer, cfg.Reload, logger)
 		}
 	}
+}
 
-	return nil
+// reloadStdioServer applies the configuration returned by reload to ghServer.
+// Failures are logged and leave the server running with its current configuration.
+func reloadStdioServer(ghServer *gitHubServer, reload func() (StdioServerConfig, error), logger *slog.Logger) {
+	if reload == nil {
+		logger.Info("ignoring SIGHUP, configuration reloading is not supported")
+		return
+	}
+
+	cfg, err := reload()
+	if err != nil {
+		logger.Error("failed to reload configuration", "error", err)
+		return
+	}
+
+	t, _ := translations.TranslationHelperWithOverrides(cfg.TranslationOverrides)
+	changes, err := ghServer.reload(cfg.mcpServerConfig(t))
+	if err != nil {
+		logger.Error("failed to reload configuration", "error", err)
+		return
+	}
+	logger.Info("reloaded configuration", "toolsAdded", changes.Added, "toolsRemoved", changes.Removed, "toolsUpdated", changes.Updated)
+}
+
+// newLogger creates the server logger, which redacts secrets found by redactor.
+// Logs are written to the file at logFilePath when one is given, and to stderr otherwise.
+func newLogger(logFilePath string, redactor *mcplog.Redactor) (*slog.Logger, io.Writer, error) {
+	if logFilePath == "" {
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+		return slog.New(mcplog.NewRedactingHandler(handler, redactor)), os.Stderr, nil
+	}
+
+	file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	handler := slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(mcplog.NewRedactingHandler(handler, redactor)), file, nil
 }
 
 type apiHost struct {
+	// product names the kind of GitHub instance, for diagnostics
+	product     string
+	webURL      *url.URL
 	baseRESTURL *url.URL
 	graphqlURL  *url.URL
 	uploadURL   *url.URL
 	rawURL      *url.URL
+
+	// subdomainIsolation is set for GitHub Enterprise Server instances found to
+	// serve uploads and raw content from subdomains
+	subdomainIsolation bool
 }
 
 func newDotcomHost() (apiHost, error) {
+	webURL, err := url.Parse("https://github.com/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse dotcom web URL: %w", err)
+	}
+
 	baseRestURL, err := url.Parse("https://api.github.com/")
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom REST URL: %w", err)
@@ -314,6 +964,8 @@ func newDotcomHost() (apiHost, error) {
 	}
 
 	return apiHost{
+		product:     "GitHub.com",
+		webURL:      webURL,
 		baseRESTURL: baseRestURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
@@ -332,6 +984,11 @@ func newGHECHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("GHEC URL must be HTTPS")
 	}
 
+	webURL, err := url.Parse(fmt.Sprintf("https://%s/", u.Hostname()))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHEC web URL: %w", err)
+	}
+
 	restURL, err := url.Parse(fmt.Sprintf("https://api.%s/", u.Hostname()))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC REST URL: %w", err)
@@ -353,6 +1010,8 @@ func newGHECHost(hostname string) (apiHost, error) {
 	}
 
 	return apiHost{
+		product:     "GHE.com",
+		webURL:      webURL,
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
@@ -360,33 +1019,38 @@ func newGHECHost(hostname string) (apiHost, error) {
 	}, nil
 }
 
-func newGHESHost(hostname string) (apiHost, error) {
+func newGHESHost(hostname string, checkIsolation bool, transport http.RoundTripper) (apiHost, error) {
 	u, err := url.Parse(hostname)
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES URL: %w", err)
 	}
 
-	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Hostname()))
+	webURL, err := url.Parse(fmt.Sprintf("%s://%s/", u.Scheme, u.Host))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHES web URL: %w", err)
+	}
+
+	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES REST URL: %w", err)
 	}
 
-	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Hostname()))
+	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES GraphQL URL: %w", err)
 	}
 
-	// Check if subdomain isolation is enabled
+	// Check if subdomain isolation is enabled, which a loopback address can't have
 	// See https://docs.github.com/en/enterprise-server@3.17/admin/configuring-settings/hardening-security-for-your-enterprise/enabling-subdomain-isolation#about-subdomain-isolation
-	hasSubdomainIsolation := checkSubdomainIsolation(u.Scheme, u.Hostname())
+	hasSubdomainIsolation := checkIsolation && !isLoopback(u.Hostname()) && checkSubdomainIsolation(transport, u.Scheme, u.Host)
 
 	var uploadURL *url.URL
 	if hasSubdomainIsolation {
 		// With subdomain isolation: https://uploads.hostname/
-		uploadURL, err = url.Parse(fmt.Sprintf("%s://uploads.%s/", u.Scheme, u.Hostname()))
+		uploadURL, err = url.Parse(fmt.Sprintf("%s://uploads.%s/", u.Scheme, u.Host))
 	} else {
 		// Without subdomain isolation: https://hostname/api/uploads/
-		uploadURL, err = url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Hostname()))
+		uploadURL, err = url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host))
 	}
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Upload URL: %w", err)
@@ -395,16 +1059,55 @@ func newGHESHost(hostname string) (apiHost, error) {
 	var rawURL *url.URL
 	if hasSubdomainIsolation {
 		// With subdomain isolation: https://raw.hostname/
-		rawURL, err = url.Parse(fmt.Sprintf("%s://raw.%s/", u.Scheme, u.Hostname()))
+		rawURL, err = url.Parse(fmt.Sprintf("%s://raw.%s/", u.Scheme, u.Host))
 	} else {
 		// Without subdomain isolation: https://hostname/raw/
-		rawURL, err = url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Hostname()))
+		rawURL, err = url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Host))
 	}
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Raw URL: %w", err)
 	}
 
 	return apiHost{
+		product:            "GitHub Enterprise Server",
+		webURL:             webURL,
+		baseRESTURL:        restURL,
+		graphqlURL:         gqlURL,
+		uploadURL:          uploadURL,
+		rawURL:             rawURL,
+		subdomainIsolation: hasSubdomainIsolation,
+	}, nil
+}
+
+// newCustomHost derives the endpoints from an explicitly configured REST API URL,
+// assuming the GitHub Enterprise Server layout without subdomain isolation for
+// the endpoints that are not configured explicitly as well.
+func newCustomHost(restURL *url.URL) (apiHost, error) {
+	origin := fmt.Sprintf("%s://%s", restURL.Scheme, restURL.Host)
+
+	webURL, err := url.Parse(origin + "/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse web URL: %w", err)
+	}
+
+	gqlURL, err := url.Parse(origin + "/api/graphql")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GraphQL URL: %w", err)
+	}
+
+	uploadURL, err := url.Parse(origin + "/api/uploads/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse Upload URL: %w", err)
+	}
+
+	rawURL, err := url.Parse(origin + "/raw/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse Raw URL: %w", err)
+	}
+
+	return apiHost{
+		product:     "custom endpoints",
+		webURL:      webURL,
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
@@ -414,11 +1117,12 @@ func newGHESHost(hostname string) (apiHost, error) {
 
 // checkSubdomainIsolation detects if GitHub Enterprise Server has subdomain isolation enabled
 // by attempting to ping the raw.<host>/_ping endpoint on the subdomain. The raw subdomain must always exist for subdomain isolation.
-func checkSubdomainIsolation(scheme, hostname string) bool {
+func checkSubdomainIsolation(transport http.RoundTripper, scheme, hostname string) bool {
 	subdomainURL := fmt.Sprintf("%s://raw.%s/_ping", scheme, hostname)
 
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Transport: transport,
+		Timeout:   5 * time.Second,
 		// Don't follow redirects - we just want to check if the endpoint exists
 		//nolint:revive // parameters are required by http.Client.CheckRedirect signature
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -435,8 +1139,65 @@ func checkSubdomainIsolation(scheme, hostname string) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-// Note that this does not handle ports yet, so development environments are out.
-func parseAPIHost(s string) (apiHost, error) {
+// APIURLs are explicitly configured API endpoints, e.g. to point the server at a
+// GitHub API stand-in on localhost. Empty fields keep the endpoint derived from
+// the host.
+type APIURLs struct {
+	// REST API base URL, e.g. http://localhost:8080/api/v3/. The other endpoints
+	// default to the GitHub Enterprise Server layout on the same origin.
+	REST string
+
+	// GraphQL API URL, e.g. http://localhost:8080/api/graphql
+	GraphQL string
+
+	// Upload API base URL, e.g. http://localhost:8080/api/uploads/
+	Upload string
+
+	// Raw content base URL, e.g. http://localhost:8080/raw/
+	Raw string
+}
+
+// parseAPIHost determines the API endpoints for the host s, applying the explicitly
+// configured urls. transport is used to probe GitHub Enterprise Server for
+// subdomain isolation, nil meaning http.DefaultTransport.
+func parseAPIHost(s string, urls APIURLs, transport http.RoundTripper) (apiHost, error) {
+	host, err := parseHost(s, urls, transport)
+	if err != nil {
+		return apiHost{}, err
+	}
+
+	for _, override := range []struct {
+		name  string
+		value string
+		dst   **url.URL
+		isDir bool
+	}{
+		{"GraphQL", urls.GraphQL, &host.graphqlURL, false},
+		{"upload", urls.Upload, &host.uploadURL, true},
+		{"raw", urls.Raw, &host.rawURL, true},
+	} {
+		if override.value == "" {
+			continue
+		}
+		u, err := parseEndpointURL(override.name, override.value, override.isDir)
+		if err != nil {
+			return apiHost{}, err
+		}
+		*override.dst = u
+	}
+
+	return host, nil
+}
+
+func parseHost(s string, urls APIURLs, transport http.RoundTripper) (apiHost, error) {
+	if urls.REST != "" {
+		restURL, err := parseEndpointURL("REST", urls.REST, true)
+		if err != nil {
+			return apiHost{}, err
+		}
+		return newCustomHost(restURL)
+	}
+
 	if s == "" {
 		return newDotcomHost()
 	}
@@ -458,7 +1219,37 @@ func parseAPIHost(s string) (apiHost, error) {
 		return newGHECHost(s)
 	}
 
-	return newGHESHost(s)
+	// The subdomain check only decides the upload and raw URLs, so it is skipped
+	// when both are configured explicitly
+	return newGHESHost(s, urls.Upload == "" || urls.Raw == "", transport)
+}
+
+// parseEndpointURL validates an explicitly configured endpoint. Base URLs that
+// paths are resolved against get a trailing slash, as go-github requires.
+func parseEndpointURL(name, s string, isDir bool) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s URL: %s", name, s)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%s URL must have a scheme (http or https): %s", name, s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%s URL must have a host: %s", name, s)
+	}
+	if isDir && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// isLoopback reports whether hostname refers to the local machine.
+func isLoopback(hostname string) bool {
+	if hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
 }
 
 type userAgentTransport struct {
@@ -482,3 +1273,28 @@ func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	req.Header.Set("Authorization", "Bearer "+t.token)
 	return t.transport.RoundTrip(req)
 }
+
+type tokenCtxKey struct{}
+
+// ContextWithToken returns a copy of ctx carrying the GitHub token that should be
+// used for API requests made on its behalf. It takes precedence over the token
+// configured on the server.
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenCtxKey{}, token)
+}
+
+func tokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenCtxKey{}).(string)
+	return token, ok && token != ""
+}
+
+// userAgent builds the user agent for API requests, including the MCP client
+// info when the session the request belongs to has completed initialization.
+func userAgent(ctx context.Context, version string) string {
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+		if info := session.GetClientInfo(); info.Name != "" {
+			return fmt.Sprintf("github-mcp-server/%s (%s/%s)", version, info.Name, info.Version)
+		}
+	}
+	return fmt.Sprintf("github-mcp-server/%s", version)
+}
@@ -0,0 +1,353 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAPIHost(t *testing.T) {
+	type endpoints struct {
+		web, rest, graphql, upload, raw string
+	}
+
+	tests := []struct {
+		name        string
+		host        string
+		urls        APIURLs
+		expected    endpoints
+		expectedErr string
+	}{
+		{
+			name: "dotcom by default",
+			expected: endpoints{
+				web:     "https://github.com/",
+				rest:    "https://api.github.com/",
+				graphql: "https://api.github.com/graphql",
+				upload:  "https://uploads.github.com",
+				raw:     "https://raw.githubusercontent.com/",
+			},
+		},
+		{
+			name: "ghe.com",
+			host: "https://octocorp.ghe.com",
+			expected: endpoints{
+				web:     "https://octocorp.ghe.com/",
+				rest:    "https://api.octocorp.ghe.com/",
+				graphql: "https://api.octocorp.ghe.com/graphql",
+				upload:  "https://uploads.octocorp.ghe.com",
+				raw:     "https://raw.octocorp.ghe.com/",
+			},
+		},
+		{
+			name: "GHES on localhost keeps the port",
+			host: "http://localhost:8080",
+			expected: endpoints{
+				web:     "http://localhost:8080/",
+				rest:    "http://localhost:8080/api/v3/",
+				graphql: "http://localhost:8080/api/graphql",
+				upload:  "http://localhost:8080/api/uploads/",
+				raw:     "http://localhost:8080/raw/",
+			},
+		},
+		{
+			name: "explicit REST URL derives the other endpoints from its origin",
+			host: "https://github.example.com",
+			urls: APIURLs{REST: "http://127.0.0.1:9000/api/v3"},
+			expected: endpoints{
+				web:     "http://127.0.0.1:9000/",
+				rest:    "http://127.0.0.1:9000/api/v3/",
+				graphql: "http://127.0.0.1:9000/api/graphql",
+				upload:  "http://127.0.0.1:9000/api/uploads/",
+				raw:     "http://127.0.0.1:9000/raw/",
+			},
+		},
+		{
+			name: "all endpoints explicit",
+			urls: APIURLs{
+				REST:    "http://localhost:9000/rest/",
+				GraphQL: "http://localhost:9001/graphql",
+				Upload:  "http://localhost:9002/uploads",
+				Raw:     "http://localhost:9003/",
+			},
+			expected: endpoints{
+				web:     "http://localhost:9000/",
+				rest:    "http://localhost:9000/rest/",
+				graphql: "http://localhost:9001/graphql",
+				upload:  "http://localhost:9002/uploads/",
+				raw:     "http://localhost:9003/",
+			},
+		},
+		{
+			name: "explicit upload and raw URLs skip the subdomain isolation check",
+			host: "https://github.example.com:8443",
+			urls: APIURLs{
+				Upload: "https://github.example.com:8443/api/uploads/",
+				Raw:    "https://github.example.com:8443/raw/",
+			},
+			expected: endpoints{
+				web:     "https://github.example.com:8443/",
+				rest:    "https://github.example.com:8443/api/v3/",
+				graphql: "https://github.example.com:8443/api/graphql",
+				upload:  "https://github.example.com:8443/api/uploads/",
+				raw:     "https://github.example.com:8443/raw/",
+			},
+		},
+		{
+			name:        "host without scheme",
+			host:        "github.example.com",
+			expectedErr: "host must have a scheme (http or https): github.example.com",
+		},
+		{
+			name:        "endpoint without scheme",
+			urls:        APIURLs{GraphQL: "localhost:9000/graphql"},
+			expectedErr: "GraphQL URL must have a scheme (http or https): localhost:9000/graphql",
+		},
+		{
+			name:        "endpoint without host",
+			urls:        APIURLs{REST: "http:///api/v3/"},
+			expectedErr: "REST URL must have a host: http:///api/v3/",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host, err := parseAPIHost(tc.host, tc.urls, nil)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, endpoints{
+				web:     host.webURL.String(),
+				rest:    host.baseRESTURL.String(),
+				graphql: host.graphqlURL.String(),
+				upload:  host.uploadURL.String(),
+				raw:     host.rawURL.String(),
+			})
+		})
+	}
+}
+
+// listTools returns the descriptions of the tools the server offers, keyed by name.
+func listTools(t *testing.T, mcpServer *server.MCPServer) map[string]string {
+	t.Helper()
+	msg := mcpServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", msg)
+	result, ok := resp.Result.(mcp.ListToolsResult)
+	require.True(t, ok)
+
+	tools := make(map[string]string, len(result.Tools))
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool.Description
+	}
+	return tools
+}
+
+func Test_newGitHubServer_toolPatterns(t *testing.T) {
+	t.Run("excluded tools are not offered", func(t *testing.T) {
+		ghServer, err := newGitHubServer(MCPServerConfig{
+			Version:           "test",
+			EnabledToolsets:   []string{"context", "pull_requests"},
+			ExcludedTools:     []string{"merge_pull_request", "create_*"},
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		tools := listTools(t, ghServer.mcpServer)
+		assert.Contains(t, tools, "get_me")
+		assert.Contains(t, tools, "pull_request_read")
+		assert.Contains(t, tools, "update_pull_request")
+		assert.NotContains(t, tools, "merge_pull_request")
+		assert.NotContains(t, tools, "create_pull_request")
+	})
+
+	t.Run("only enabled tools of enabled toolsets are offered", func(t *testing.T) {
+		ghServer, err := newGitHubServer(MCPServerConfig{
+			Version:           "test",
+			EnabledToolsets:   []string{"context", "issues"},
+			EnabledTools:      []string{"get_*", "list_pull_requests"},
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		tools := listTools(t, ghServer.mcpServer)
+		assert.Contains(t, tools, "get_me")
+		assert.Contains(t, tools, "get_issue")
+		assert.NotContains(t, tools, "list_issues")
+		assert.NotContains(t, tools, "list_pull_requests", "the pull_requests toolset is not enabled")
+	})
+
+	t.Run("dynamic toolsets", func(t *testing.T) {
+		ghServer, err := newGitHubServer(MCPServerConfig{
+			Version:           "test",
+			DynamicToolsets:   true,
+			ExcludedTools:     []string{"merge_pull_request"},
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		msg := ghServer.mcpServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_toolset_tools","arguments":{"toolset":"pull_requests"}}}`))
+		resp, ok := msg.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", msg)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		text := resultText(&result)
+		assert.Contains(t, text, `"pull_request_read"`)
+		assert.NotContains(t, text, `"merge_pull_request"`)
+
+		msg = ghServer.mcpServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"enable_toolset","arguments":{"toolset":"pull_requests"}}}`))
+		_, ok = msg.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", msg)
+		tools := listTools(t, ghServer.mcpServer)
+		assert.Contains(t, tools, "pull_request_read")
+		assert.NotContains(t, tools, "merge_pull_request")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := newGitHubServer(MCPServerConfig{
+			Version:           "test",
+			EnabledToolsets:   []string{"context"},
+			ExcludedTools:     []string{"delete_["},
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		assert.ErrorContains(t, err, `invalid tool pattern "delete_["`)
+	})
+}
+
+func Test_newGitHubServer_allowedRepositories(t *testing.T) {
+	ghServer, err := newGitHubServer(MCPServerConfig{
+		Version:             "test",
+		EnabledToolsets:     []string{"context", "issues", "gists"},
+		AllowedRepositories: []string{"octo-org/app"},
+		Translator:          translations.NullTranslationHelper,
+		ContentWindowSize:   5000,
+	})
+	require.NoError(t, err)
+
+	tools := listTools(t, ghServer.mcpServer)
+	assert.Contains(t, tools, "get_me")
+	assert.Contains(t, tools, "get_issue")
+	assert.NotContains(t, tools, "list_gists", "gists can't be restricted to repositories")
+
+	msg := ghServer.mcpServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_issue","arguments":{"owner":"octo-org","repo":"website","issue_number":1}}}`))
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", msg)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "repository octo-org/website is outside the repositories this server is allowed to access", resultText(&result))
+
+	_, err = newGitHubServer(MCPServerConfig{
+		Version:             "test",
+		AllowedRepositories: []string{"octo-org/app/extra"},
+		Translator:          translations.NullTranslationHelper,
+	})
+	assert.ErrorContains(t, err, "invalid repository scope")
+}
+
+func Test_gitHubServer_reload(t *testing.T) {
+	ghServer, err := newGitHubServer(MCPServerConfig{
+		Version:           "test",
+		EnabledToolsets:   []string{"context"},
+		Translator:        translations.NullTranslationHelper,
+		ContentWindowSize: 5000,
+	})
+	require.NoError(t, err)
+
+	listTools := func() map[string]string {
+		return listTools(t, ghServer.mcpServer)
+	}
+	require.Contains(t, listTools(), "get_me")
+	require.NotContains(t, listTools(), "get_issue")
+
+	t.Run("enabling toolsets and read-only mode", func(t *testing.T) {
+		changes, err := ghServer.reload(MCPServerConfig{
+			EnabledToolsets:   []string{"context", "issues"},
+			ReadOnly:          true,
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, changes.Added, "get_issue")
+		assert.NotContains(t, changes.Added, "create_issue")
+		assert.Empty(t, changes.Removed)
+		tools := listTools()
+		assert.Contains(t, tools, "get_issue")
+		assert.NotContains(t, tools, "create_issue")
+	})
+
+	t.Run("changed descriptions", func(t *testing.T) {
+		changes, err := ghServer.reload(MCPServerConfig{
+			EnabledToolsets: []string{"context"},
+			Translator: func(key, defaultValue string) string {
+				if key == "TOOL_GET_ME_DESCRIPTION" {
+					return "Who am I?"
+				}
+				return defaultValue
+			},
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, changes.Added)
+		assert.Contains(t, changes.Removed, "get_issue")
+		assert.Equal(t, []string{"get_me"}, changes.Updated)
+		tools := listTools()
+		assert.Equal(t, "Who am I?", tools["get_me"])
+		assert.NotContains(t, tools, "get_issue")
+	})
+
+	t.Run("settings that require a restart are kept", func(t *testing.T) {
+		_, err := ghServer.reload(MCPServerConfig{
+			Host:              "https://github.example.com",
+			DynamicToolsets:   true,
+			EnabledToolsets:   []string{"context"},
+			Translator:        translations.NullTranslationHelper,
+			ContentWindowSize: 5000,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, ghServer.cfg.Host)
+		assert.False(t, ghServer.cfg.DynamicToolsets)
+		assert.NotContains(t, listTools(), "enable_toolset")
+	})
+}
+
+func Test_gitHubServer_reloadDynamicToolsets(t *testing.T) {
+	ghServer, err := newGitHubServer(MCPServerConfig{
+		Version:           "test",
+		DynamicToolsets:   true,
+		Translator:        translations.NullTranslationHelper,
+		ContentWindowSize: 5000,
+	})
+	require.NoError(t, err)
+
+	changes, err := ghServer.reload(MCPServerConfig{
+		ExcludedTools:     []string{"create_issue"},
+		Translator:        translations.NullTranslationHelper,
+		ContentWindowSize: 5000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, toolChanges{}, changes, "the dynamic tools themselves don't change")
+
+	msg := ghServer.mcpServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"enable_toolset","arguments":{"toolset":"issues"}}}`))
+	_, ok := msg.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", msg)
+	tools := listTools(t, ghServer.mcpServer)
+	assert.Contains(t, tools, "get_issue")
+	assert.NotContains(t, tools, "create_issue", "toolsets enabled after the reload use the reloaded settings")
+}
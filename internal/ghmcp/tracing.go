@@ -0,0 +1,172 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// tracerShutdownTimeout bounds the export of the remaining spans on shutdown.
+const tracerShutdownTimeout = 5 * time.Second
+
+// traceparentMetaKey is the key of the W3C traceparent in the _meta of a tool
+// call, for clients that trace the calls they make.
+const traceparentMetaKey = "traceparent"
+
+// newTracer returns the tracer configured by cfg, or nil when tracing is
+// disabled. Unless cfg has a client, spans are exported through the proxy and
+// with the TLS settings of network, like the requests to GitHub.
+func newTracer(cfg tracing.Config, network NetworkConfig, version string, logger *slog.Logger) (*tracing.Tracer, error) {
+	if cfg.Endpoint == "" {
+		return nil, nil
+	}
+	if cfg.Client == nil {
+		transport, err := newBaseTransport(network)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure network: %w", err)
+		}
+		cfg.Client = &http.Client{Transport: transport}
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "github-mcp-server"
+	}
+	if cfg.ServiceVersion == "" {
+		cfg.ServiceVersion = version
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logger
+	}
+	tracer, err := tracing.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	return tracer, nil
+}
+
+// shutdownTracer exports the spans that haven't been exported yet.
+func shutdownTracer(tracer *tracing.Tracer, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		logger.Warn("failed to export remaining spans", "error", err)
+	}
+}
+
+// tracingMiddleware records a span for every tool call. It is a tool handler
+// middleware rather than a hook so that the span can be passed on through the
+// context, making the API requests of the call its children.
+func tracingMiddleware(tracer *tracing.Tracer) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if tracer == nil {
+			return next
+		}
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if meta := request.Params.Meta; meta != nil {
+				if traceparent, ok := meta.AdditionalFields[traceparentMetaKey].(string); ok {
+					ctx = tracing.ContextWithRemoteParent(ctx, traceparent)
+				}
+			}
+
+			ctx, span := tracer.Start(ctx, "tools/call "+request.Params.Name, tracing.SpanKindServer,
+				tracing.String("mcp.method.name", string(mcp.MethodToolsCall)),
+				tracing.String("mcp.tool.name", request.Params.Name),
+			)
+			defer span.End()
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.SetError(err.Error())
+			case result != nil && result.IsError:
+				span.SetAttributes(tracing.Bool("mcp.tool.is_error", true))
+				span.SetError(resultText(result))
+			}
+			if recorder := rateLimitRecorderFromContext(ctx); recorder != nil {
+				if limit, ok := recorder.get(); ok {
+					span.SetAttributes(rateLimitAttributes(limit)...)
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// resultText returns the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// tracingTransport records a span for every request sent to GitHub, as a child
+// of the tool call span in the request's context.
+type tracingTransport struct {
+	transport http.RoundTripper
+	tracer    *tracing.Tracer
+	api       string
+}
+
+func newTracingTransport(transport http.RoundTripper, tracer *tracing.Tracer, api string) http.RoundTripper {
+	if tracer == nil {
+		return transport
+	}
+	return &tracingTransport{transport: transport, tracer: tracer, api: api}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, span := t.tracer.Start(req.Context(), req.Method, tracing.SpanKindClient,
+		tracing.String("github.api", t.api),
+		tracing.String("http.request.method", req.Method),
+		tracing.String("server.address", req.URL.Hostname()),
+		tracing.String("url.path", req.URL.Path),
+	)
+	defer span.End()
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		span.SetError(err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetError(resp.Status)
+	}
+	if limit, ok := parseRateLimit(resp.Header); ok {
+		span.SetAttributes(rateLimitAttributes(limit)...)
+	}
+	return resp, nil
+}
+
+func rateLimitAttributes(limit *rateLimit) []tracing.Attribute {
+	attrs := []tracing.Attribute{
+		tracing.Int("github.rate_limit.limit", limit.Limit),
+		tracing.Int("github.rate_limit.remaining", limit.Remaining),
+		tracing.Int("github.rate_limit.used", limit.Used),
+	}
+	if limit.Resource != "" {
+		attrs = append(attrs, tracing.String("github.rate_limit.resource", limit.Resource))
+	}
+	if limit.Reset != "" {
+		attrs = append(attrs, tracing.String("github.rate_limit.reset", limit.Reset))
+	}
+	return attrs
+}
+
+// contextWithTraceparentHeader makes the W3C traceparent header of an HTTP
+// request the remote parent of the spans recorded while handling it.
+func contextWithTraceparentHeader(ctx context.Context, r *http.Request) context.Context {
+	if traceparent := r.Header.Get("traceparent"); traceparent != "" {
+		return tracing.ContextWithRemoteParent(ctx, traceparent)
+	}
+	return ctx
+}
@@ -0,0 +1,160 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// exportedSpan is a span received by the test receiver, with its IDs hex-encoded.
+type exportedSpan struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Attributes   map[string]any
+	Status       *tracepb.Status
+}
+
+func (s exportedSpan) attribute(key string) any {
+	return s.Attributes[key]
+}
+
+// newTestTracer returns a tracer exporting to a test receiver, which is only
+// trusted through the CA bundle of the network configuration, and a function
+// that shuts the tracer down and returns the exported spans by name.
+func newTestTracer(t *testing.T) (*tracing.Tracer, func() map[string]exportedSpan) {
+	var mu sync.Mutex
+	spans := map[string]exportedSpan{}
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var request coltracepb.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &request))
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range request.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					exported := exportedSpan{
+						TraceID:      hex.EncodeToString(span.TraceId),
+						SpanID:       hex.EncodeToString(span.SpanId),
+						ParentSpanID: hex.EncodeToString(span.ParentSpanId),
+						Name:         span.Name,
+						Attributes:   map[string]any{},
+					}
+					for _, attr := range span.Attributes {
+						switch value := attr.Value.Value.(type) {
+						case *commonpb.AnyValue_StringValue:
+							exported.Attributes[attr.Key] = value.StringValue
+						case *commonpb.AnyValue_IntValue:
+							exported.Attributes[attr.Key] = value.IntValue
+						case *commonpb.AnyValue_BoolValue:
+							exported.Attributes[attr.Key] = value.BoolValue
+						}
+					}
+					if span.Status.GetCode() == tracepb.Status_STATUS_CODE_ERROR {
+						exported.Status = span.Status
+					}
+					spans[span.Name] = exported
+				}
+			}
+		}
+	}))
+	t.Cleanup(receiver.Close)
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: receiver.Certificate().Raw}), 0600))
+
+	tracer, err := newTracer(tracing.Config{Endpoint: receiver.URL}, NetworkConfig{CABundlePath: caBundle}, "test", slog.Default())
+	require.NoError(t, err)
+
+	return tracer, func() map[string]exportedSpan {
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		mu.Lock()
+		defer mu.Unlock()
+		return spans
+	}
+}
+
+func Test_tracing(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Used", "679")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer github.Close()
+
+	tracer, exportedSpans := newTestTracer(t)
+	client := &http.Client{Transport: newRateLimitTransport(newTracingTransport(http.DefaultTransport, tracer, "rest"))}
+
+	handler := rateLimitMiddleware(tracingMiddleware(tracer)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, github.URL+"/repos/octocat/hello-world", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return mcp.NewToolResultError("failed to get repository: 404 Not Found"), nil
+	}))
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_repository"
+	request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}}
+	_, err := handler(context.Background(), request)
+	require.NoError(t, err)
+
+	spans := exportedSpans()
+	require.Len(t, spans, 2)
+	toolSpan, requestSpan := spans["tools/call get_repository"], spans["GET"]
+
+	// The tool call continues the client's trace
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", toolSpan.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", toolSpan.ParentSpanID)
+	assert.Equal(t, "get_repository", toolSpan.attribute("mcp.tool.name"))
+	assert.Equal(t, true, toolSpan.attribute("mcp.tool.is_error"))
+	assert.Equal(t, int64(4321), toolSpan.attribute("github.rate_limit.remaining"))
+	require.NotNil(t, toolSpan.Status)
+	assert.Equal(t, "failed to get repository: 404 Not Found", toolSpan.Status.Message)
+
+	// API requests are children of the tool call
+	assert.Equal(t, toolSpan.TraceID, requestSpan.TraceID)
+	assert.Equal(t, toolSpan.SpanID, requestSpan.ParentSpanID)
+	assert.Equal(t, "rest", requestSpan.attribute("github.api"))
+	assert.Equal(t, "/repos/octocat/hello-world", requestSpan.attribute("url.path"))
+	assert.Equal(t, int64(404), requestSpan.attribute("http.response.status_code"))
+	assert.Equal(t, int64(5000), requestSpan.attribute("github.rate_limit.limit"))
+	assert.Equal(t, "core", requestSpan.attribute("github.rate_limit.resource"))
+	require.NotNil(t, requestSpan.Status)
+	assert.Equal(t, "404 Not Found", requestSpan.Status.Message)
+}
+
+func Test_tracing_Disabled(t *testing.T) {
+	transport := http.DefaultTransport
+	assert.Same(t, transport, newTracingTransport(transport, nil, "rest"))
+
+	tracer, err := newTracer(tracing.Config{}, NetworkConfig{}, "test", nil)
+	require.NoError(t, err)
+	assert.Nil(t, tracer)
+}
@@ -0,0 +1,102 @@
+package ghmcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// NetworkConfig configures how the server connects to GitHub, for networks where
+// the defaults don't work, e.g. behind a TLS-inspecting corporate proxy.
+type NetworkConfig struct {
+	// CABundlePath is a PEM file with certificates to trust in addition to the system roots
+	CABundlePath string
+
+	// ProxyURL is the proxy to send requests through. When empty, the proxy is
+	// taken from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+
+	// NoProxy lists the hosts to connect to directly when ProxyURL is set, with
+	// the conventions of the NO_PROXY environment variable: hostnames, which also
+	// match their subdomains, or with a leading dot only their subdomains, IP
+	// addresses or CIDR ranges, optionally with a port, or "*" to bypass the proxy
+	// entirely. Requests to localhost and loopback addresses never use the proxy.
+	NoProxy []string
+
+	// ClientCertPath and ClientKeyPath are the PEM encoded certificate and key to
+	// present when the server requires mutual TLS
+	ClientCertPath string
+	ClientKeyPath  string
+}
+
+// newBaseTransport creates the transport all connections to GitHub are made with.
+func newBaseTransport(cfg NetworkConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.CABundlePath != "" || cfg.ClientCertPath != "" || cfg.ClientKeyPath != "" {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse proxy URL: %s", cfg.ProxyURL)
+		}
+		if proxyURL.Scheme != "http" && proxyURL.Scheme != "https" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("proxy URL must be an http or https URL: %s", cfg.ProxyURL)
+		}
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  proxyURL.String(),
+			HTTPSProxy: proxyURL.String(),
+			NoProxy:    strings.Join(cfg.NoProxy, ","),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	return transport, nil
+}
+
+func newTLSConfig(cfg NetworkConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CABundlePath != "" {
+		pem, err := os.ReadFile(cfg.CABundlePath) // #nosec G304 - path is provided by the operator
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundlePath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertPath != "" || cfg.ClientKeyPath != "" {
+		if cfg.ClientCertPath == "" || cfg.ClientKeyPath == "" {
+			return nil, errors.New("client certificate and key must be configured together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,133 @@
+package ghmcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newBaseTransport_Proxy(t *testing.T) {
+	transport, err := newBaseTransport(NetworkConfig{
+		ProxyURL: "http://proxy.example.com:3128",
+		NoProxy:  []string{"internal.example.com", ".corp.example.com", "ghes.example.com:8443", "10.0.0.0/8", "192.168.1.1", ""},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		url     string
+		proxied bool
+	}{
+		{"https://api.github.com/user", true},
+		{"http://api.github.com/user", true},
+		{"https://internal.example.com/api/v3/", false},
+		{"https://api.internal.example.com/", false},
+		{"https://notinternal.example.com/", true},
+		{"https://git.corp.example.com/", false},
+		{"https://corp.example.com/", true},
+		{"https://ghes.example.com:8443/", false},
+		{"https://ghes.example.com/", true},
+		{"https://10.1.2.3/", false},
+		{"https://11.1.2.3/", true},
+		{"http://192.168.1.1/", false},
+		{"http://localhost:8080/api/v3/user", false},
+		{"http://[::1]:9090/", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			proxyURL, err := transport.Proxy(httptest.NewRequest(http.MethodGet, tc.url, nil))
+			require.NoError(t, err)
+			if tc.proxied {
+				require.NotNil(t, proxyURL)
+				assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+			} else {
+				assert.Nil(t, proxyURL)
+			}
+		})
+	}
+
+	t.Run("wildcard bypasses the proxy for every host", func(t *testing.T) {
+		transport, err := newBaseTransport(NetworkConfig{ProxyURL: "http://proxy.example.com:3128", NoProxy: []string{"*"}})
+		require.NoError(t, err)
+		proxyURL, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "https://api.github.com/", nil))
+		require.NoError(t, err)
+		assert.Nil(t, proxyURL)
+	})
+
+	_, err = newBaseTransport(NetworkConfig{ProxyURL: "proxy.example.com:3128"})
+	assert.EqualError(t, err, "proxy URL must be an http or https URL: proxy.example.com:3128")
+}
+
+func Test_newBaseTransport_TLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+		return path
+	}
+
+	// The test server's certificate doubles as client certificate
+	cert := srv.TLS.Certificates[0]
+	caBundle := writePEM("ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	certFile := writePEM("cert.pem", "CERTIFICATE", cert.Certificate[0])
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	keyFile := writePEM("key.pem", "PRIVATE KEY", keyDER)
+
+	get := func(cfg NetworkConfig) (int, error) {
+		transport, err := newBaseTransport(cfg)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return resp.StatusCode, nil
+	}
+
+	t.Run("untrusted certificate is rejected", func(t *testing.T) {
+		_, err := get(NetworkConfig{})
+		assert.Error(t, err)
+	})
+
+	t.Run("CA bundle is trusted", func(t *testing.T) {
+		status, err := get(NetworkConfig{CABundlePath: caBundle})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("client certificate is presented", func(t *testing.T) {
+		status, err := get(NetworkConfig{CABundlePath: caBundle, ClientCertPath: certFile, ClientKeyPath: keyFile})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("client certificate requires a key", func(t *testing.T) {
+		_, err := newBaseTransport(NetworkConfig{ClientCertPath: certFile})
+		assert.EqualError(t, err, "client certificate and key must be configured together")
+	})
+
+	t.Run("CA bundle without certificates", func(t *testing.T) {
+		_, err := newBaseTransport(NetworkConfig{CABundlePath: keyFile})
+		assert.EqualError(t, err, "no certificates found in CA bundle "+keyFile)
+	})
+}
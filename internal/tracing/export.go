@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config configures a Tracer.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, e.g.
+	// "http://localhost:4318". Spans are sent to its /v1/traces path.
+	Endpoint string
+
+	// Headers are added to every export request, e.g. for authentication
+	Headers map[string]string
+
+	// ServiceName and ServiceVersion describe the service in exported spans
+	ServiceName    string
+	ServiceVersion string
+
+	// Client sends the export requests, defaults to http.DefaultClient
+	Client *http.Client
+
+	// Logger reports failed exports, defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+// New returns a tracer exporting spans in batches to the OTLP/HTTP receiver
+// configured in cfg. Call Shutdown to export the remaining spans before the
+// process exits.
+func New(cfg Config) (*Tracer, error) {
+	endpoint, err := tracesURL(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithHeaders(cfg.Headers),
+		otlptracehttp.WithHTTPClient(client),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return newTracer(&loggingExporter{SpanExporter: exporter, logger: logger}, cfg), nil
+}
+
+// newTracer returns a tracer exporting spans in batches with exporter.
+func newTracer(exporter sdktrace.SpanExporter, cfg Config) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", cfg.ServiceVersion),
+		)),
+	)
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(cfg.ServiceName, trace.WithInstrumentationVersion(cfg.ServiceVersion)),
+	}
+}
+
+// tracesURL returns the URL spans are exported to for the receiver at endpoint.
+func tracesURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("OTLP endpoint must be an http or https URL: %s", endpoint)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/traces"
+	return u.String(), nil
+}
+
+// loggingExporter reports failed exports to the logger, rather than to the
+// process-wide OpenTelemetry error handler, which writes to stderr.
+type loggingExporter struct {
+	sdktrace.SpanExporter
+	logger *slog.Logger
+}
+
+func (e *loggingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil {
+		e.logger.Warn("failed to export spans", "spans", len(spans), "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package tracing records spans and exports them to an OpenTelemetry collector
+// with the OTLP/HTTP protocol, using the OpenTelemetry SDK. It wraps the SDK in
+// the small API the server needs: spans with attributes and a status, parent
+// propagation through contexts and W3C traceparent values, and nil tracers and
+// spans that record nothing.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKind describes the relationship of a span to its parent and children.
+type SpanKind = trace.SpanKind
+
+const (
+	SpanKindInternal = trace.SpanKindInternal
+	SpanKindServer   = trace.SpanKindServer
+	SpanKindClient   = trace.SpanKindClient
+)
+
+// Attribute is a key-value pair describing a span.
+type Attribute = attribute.KeyValue
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return attribute.String(key, value)
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return attribute.Int(key, value)
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return attribute.Bool(key, value)
+}
+
+// Tracer starts spans and hands them to its exporter when they end. A nil
+// *Tracer is valid and records nothing, so callers don't need to check whether
+// tracing is enabled.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// Start starts a span as a child of the span in ctx, if any, and returns a
+// context carrying the new span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span}
+}
+
+// Shutdown exports the spans that have ended and stops the exporter. Spans
+// ending afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+// Span is an operation within a trace. A nil *Span is valid and records nothing.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// SetError marks the span as failed.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, message)
+}
+
+// End completes the span and queues it for export. Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// ContextWithRemoteParent returns a context whose spans continue the trace
+// described by traceparent, a W3C Trace Context header value received from the
+// caller. Invalid values are ignored.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+func ContextWithRemoteParent(ctx context.Context, traceparent string) context.Context {
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+}
@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_Tracer(t *testing.T) {
+	exporter := keptSpansExporter{tracetest.NewInMemoryExporter()}
+	tracer := newTracer(exporter, Config{ServiceName: "test-service", ServiceVersion: "1.2.3"})
+
+	ctx, parent := tracer.Start(context.Background(), "parent", SpanKindServer, String("mcp.tool.name", "get_me"))
+	_, child := tracer.Start(ctx, "child", SpanKindClient)
+	child.SetAttributes(Int("http.response.status_code", 404), Bool("retried", false))
+	child.SetError("404 Not Found")
+	child.End()
+	child.End()
+	parent.End()
+
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	require.Len(t, spans, 2)
+	parentSpan, childSpan := spans["parent"], spans["child"]
+
+	assert.Contains(t, parentSpan.Resource.Attributes(), attribute.String("service.name", "test-service"))
+	assert.Equal(t, "1.2.3", parentSpan.InstrumentationScope.Version)
+	assert.False(t, parentSpan.Parent.IsValid())
+	assert.Equal(t, trace.SpanKindServer, parentSpan.SpanKind)
+	assert.Equal(t, codes.Unset, parentSpan.Status.Code)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("mcp.tool.name", "get_me")}, parentSpan.Attributes)
+
+	assert.Equal(t, parentSpan.SpanContext.TraceID(), childSpan.SpanContext.TraceID())
+	assert.Equal(t, parentSpan.SpanContext.SpanID(), childSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, childSpan.Status.Code)
+	assert.Equal(t, "404 Not Found", childSpan.Status.Description)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int("http.response.status_code", 404), attribute.Bool("retried", false)}, childSpan.Attributes)
+
+	t.Run("spans ending after shutdown are dropped", func(t *testing.T) {
+		_, span := tracer.Start(context.Background(), "late", SpanKindInternal)
+		span.End()
+		for _, span := range exporter.GetSpans() {
+			assert.NotEqual(t, "late", span.Name)
+		}
+	})
+}
+
+// keptSpansExporter is an in-memory exporter whose spans outlast its shutdown.
+type keptSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keptSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func Test_New(t *testing.T) {
+	var requests []*http.Request
+	var exported []*coltracepb.ExportTraceServiceRequest
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var request coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, r)
+		exported = append(exported, &request)
+	}))
+	defer receiver.Close()
+
+	var clientUsed bool
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		clientUsed = true
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	tracer, err := New(Config{
+		Endpoint:    receiver.URL + "/",
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+		ServiceName: "test-service",
+		Client:      client,
+	})
+	require.NoError(t, err)
+	_, span := tracer.Start(context.Background(), "span", SpanKindInternal)
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	require.Len(t, requests, 1)
+	assert.True(t, clientUsed, "spans are exported with the configured client")
+	assert.Equal(t, "/v1/traces", requests[0].URL.Path)
+	assert.Equal(t, "Bearer secret", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "span", exported[0].ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Tracer_Nil(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "span", SpanKindInternal)
+	assert.Equal(t, context.Background(), ctx)
+	assert.Nil(t, span)
+
+	// None of these may panic
+	span.SetAttributes(String("key", "value"))
+	span.SetError("error")
+	span.End()
+	assert.NoError(t, tracer.Shutdown(context.Background()))
+}
+
+func Test_New_InvalidEndpoint(t *testing.T) {
+	_, err := New(Config{Endpoint: "localhost:4318"})
+	assert.EqualError(t, err, "OTLP endpoint must be an http or https URL: localhost:4318")
+}
+
+func Test_ContextWithRemoteParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		valid       bool
+	}{
+		{name: "valid", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", valid: true},
+		{name: "future version with more fields", traceparent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", valid: true},
+		{name: "version 00 with more fields", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "invalid version", traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "zero trace ID", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero span ID", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "not hex", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01"},
+		{name: "empty", traceparent: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(ContextWithRemoteParent(context.Background(), tc.traceparent))
+			require.Equal(t, tc.valid, sc.IsValid())
+			if tc.valid {
+				assert.True(t, sc.IsRemote())
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+			}
+		})
+	}
+}
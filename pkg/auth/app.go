@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	// appJWTLifetime is how long the JWTs we sign are valid for. GitHub rejects
+	// anything longer than 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the issued-at claim to tolerate clock drift
+	// between this machine and GitHub.
+	appJWTClockSkew = 60 * time.Second
+	// installationTokenRefreshMargin is how long before expiry a cached
+	// installation token is replaced, so that in-flight requests never carry
+	// an expired token.
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// TokenSource provides the token used to authenticate GitHub API requests.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// AppInstallationTokenSource authenticates as an installation of a GitHub App.
+// It signs JWTs locally with the app's private key, exchanges them for
+// installation access tokens and caches each token until shortly before it
+// expires.
+type AppInstallationTokenSource struct {
+	appID          int64
+	installationID int64
+	privateKey     *rsa.PrivateKey
+	baseURL        *url.URL
+	httpClient     *http.Client
+	now            func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	expiresAt   time.Time
+	permissions map[string]string
+}
+
+// NewAppInstallationTokenSource creates a token source for the given app installation.
+// privateKeyPEM is the PEM encoded private key downloaded from the app settings, and
+// baseURL is the REST API base URL of the GitHub host the app is installed on.
+func NewAppInstallationTokenSource(appID, installationID int64, privateKeyPEM []byte, baseURL *url.URL, httpClient *http.Client) (*AppInstallationTokenSource, error) {
+	if appID <= 0 {
+		return nil, errors.New("GitHub App ID must be a positive integer")
+	}
+	if installationID <= 0 {
+		return nil, errors.New("GitHub App installation ID must be a positive integer")
+	}
+
+	privateKey, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &AppInstallationTokenSource{
+		appID:          appID,
+		installationID: installationID,
+		privateKey:     privateKey,
+		baseURL:        baseURL,
+		httpClient:     httpClient,
+		now:            time.Now,
+	}, nil
+}
+
+// Token returns a valid installation access token, requesting a new one when the
+// cached token is missing or about to expire.
+func (s *AppInstallationTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refresh(ctx); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Permissions returns the permissions granted to the installation token, keyed by
+// resource with the access level as value, e.g. "issues": "write".
+func (s *AppInstallationTokenSource) Permissions(ctx context.Context) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.permissions, nil
+}
+
+func (s *AppInstallationTokenSource) refresh(ctx context.Context) error {
+	if s.token != "" && s.now().Add(installationTokenRefreshMargin).Before(s.expiresAt) {
+		return nil
+	}
+
+	jwt, err := s.signJWT()
+	if err != nil {
+		return fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+
+	client := github.NewClient(s.httpClient).WithAuthToken(jwt)
+	client.BaseURL = s.baseURL
+
+	installationToken, _, err := client.Apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create installation token: %w", err)
+	}
+	if installationToken.GetToken() == "" {
+		return errors.New("failed to create installation token: response did not contain a token")
+	}
+
+	permissions, err := permissionsMap(installationToken.GetPermissions())
+	if err != nil {
+		return err
+	}
+
+	s.token = installationToken.GetToken()
+	s.expiresAt = installationToken.GetExpiresAt().Time
+	s.permissions = permissions
+	return nil
+}
+
+// permissionsMap converts the installation permissions into a map keyed by the
+// resource names used by the API, leaving out resources without access.
+func permissionsMap(permissions *github.InstallationPermissions) (map[string]string, error) {
+	result := map[string]string{}
+	if permissions == nil {
+		return result, nil
+	}
+	data, err := json.Marshal(permissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installation token permissions: %w", err)
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to read installation token permissions: %w", err)
+	}
+	return result, nil
+}
+
+// signJWT creates the RS256 signed JWT used to authenticate as the app itself.
+// See https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (s *AppInstallationTokenSource) signJWT() (string, error) {
+	now := s.now()
+
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseRSAPrivateKey decodes a PEM encoded RSA private key in either PKCS#1 form,
+// which is what GitHub generates, or PKCS#8 form.
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode GitHub App private key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be an RSA key, got %T", key)
+	}
+	return rsaKey, nil
+}
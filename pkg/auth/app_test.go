@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, keyPEM
+}
+
+func Test_AppInstallationTokenSource(t *testing.T) {
+	key, keyPEM := newTestPrivateKey(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var exchanges int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/app/installations/42/access_tokens", r.URL.Path)
+
+		// Verify the JWT was signed with the app's key and carries the app ID
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var claims map[string]int64
+		require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+		assert.Equal(t, int64(1234), claims["iss"])
+		assert.LessOrEqual(t, claims["exp"]-claims["iat"], int64((10 * time.Minute).Seconds()))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_token%d","expires_at":%q,"permissions":{"issues":"write","metadata":"read"}}`, exchanges, now.Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+
+	source, err := NewAppInstallationTokenSource(1234, 42, keyPEM, baseURL, server.Client())
+	require.NoError(t, err)
+	source.now = func() time.Time { return now }
+
+	t.Run("exchanges a JWT for an installation token", func(t *testing.T) {
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_token1", token)
+	})
+
+	t.Run("reuses the cached token while it is valid", func(t *testing.T) {
+		now = now.Add(30 * time.Minute)
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_token1", token)
+		assert.Equal(t, 1, exchanges)
+	})
+
+	t.Run("refreshes the token before it expires", func(t *testing.T) {
+		now = now.Add(26 * time.Minute)
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_token2", token)
+		assert.Equal(t, 2, exchanges)
+	})
+
+	t.Run("reports the permissions of the installation token", func(t *testing.T) {
+		permissions, err := source.Permissions(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"issues": "write", "metadata": "read"}, permissions)
+		assert.Equal(t, 2, exchanges)
+	})
+}
+
+func Test_NewAppInstallationTokenSource(t *testing.T) {
+	_, keyPEM := newTestPrivateKey(t)
+	baseURL, _ := url.Parse("https://api.github.com/")
+
+	t.Run("accepts PKCS#8 keys", func(t *testing.T) {
+		key, _ := newTestPrivateKey(t)
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		pkcs8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		_, err = NewAppInstallationTokenSource(1, 2, pkcs8PEM, baseURL, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects invalid PEM", func(t *testing.T) {
+		_, err := NewAppInstallationTokenSource(1, 2, []byte("not a key"), baseURL, nil)
+		assert.ErrorContains(t, err, "no PEM block found")
+	})
+
+	t.Run("rejects missing IDs", func(t *testing.T) {
+		_, err := NewAppInstallationTokenSource(0, 2, keyPEM, baseURL, nil)
+		assert.ErrorContains(t, err, "App ID")
+
+		_, err = NewAppInstallationTokenSource(1, 0, keyPEM, baseURL, nil)
+		assert.ErrorContains(t, err, "installation ID")
+	})
+}
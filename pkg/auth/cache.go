@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// ErrNoCachedToken is returned when the cache holds no token for a host.
+var ErrNoCachedToken = errors.New("no cached token")
+
+// TokenCache stores OAuth tokens obtained by `github-mcp-server login`, keyed by
+// GitHub host. The cache file is only ever readable by its owner, and tokens are
+// refused if the file permissions have been loosened.
+type TokenCache struct {
+	path string
+}
+
+// CachedToken is a single cache entry.
+type CachedToken struct {
+	Token     string    `json:"token"`
+	Scopes    string    `json:"scopes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTokenCache returns a cache backed by the file at path.
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{path: path}
+}
+
+// DefaultTokenCachePath returns the default location of the token cache in the
+// user's configuration directory.
+func DefaultTokenCachePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(dir, "github-mcp-server", "tokens.json"), nil
+}
+
+// Path returns the location of the cache file.
+func (c *TokenCache) Path() string {
+	return c.path
+}
+
+// Load returns the cached token for host, or ErrNoCachedToken if there is none.
+func (c *TokenCache) Load(host string) (*CachedToken, error) {
+	entries, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := entries[host]
+	if !ok || entry.Token == "" {
+		return nil, ErrNoCachedToken
+	}
+	return entry, nil
+}
+
+// Store saves token for host, replacing any previous entry for that host.
+func (c *TokenCache) Store(host string, token *CachedToken) error {
+	entries, err := c.read()
+	if err != nil {
+		return err
+	}
+	entries[host] = token
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %w", err)
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so that a crash never leaves a truncated
+	// cache behind. CreateTemp creates the file with 0600 permissions.
+	tmp, err := os.CreateTemp(dir, ".tokens-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create token cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+func (c *TokenCache) read() (map[string]*CachedToken, error) {
+	entries := map[string]*CachedToken{}
+
+	info, err := os.Stat(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	// Windows does not support Unix permission bits, access is governed by the
+	// ACLs of the user's profile directory instead.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("token cache %s is accessible by other users (mode %s), restrict it with chmod 600", c.path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache %s: %w", c.path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TokenCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "github-mcp-server", "tokens.json")
+	cache := NewTokenCache(path)
+
+	t.Run("missing cache has no tokens", func(t *testing.T) {
+		_, err := cache.Load("github.com")
+		assert.ErrorIs(t, err, ErrNoCachedToken)
+	})
+
+	t.Run("stores and loads tokens per host", func(t *testing.T) {
+		require.NoError(t, cache.Store("github.com", &CachedToken{Token: "gho_dotcom", CreatedAt: time.Now()}))
+		require.NoError(t, cache.Store("ghes.example.com", &CachedToken{Token: "gho_ghes", CreatedAt: time.Now()}))
+
+		token, err := cache.Load("github.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gho_dotcom", token.Token)
+
+		token, err = cache.Load("ghes.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gho_ghes", token.Token)
+	})
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	t.Run("cache file is only accessible by its owner", func(t *testing.T) {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		dirInfo, err := os.Stat(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+	})
+
+	t.Run("refuses a cache readable by others", func(t *testing.T) {
+		require.NoError(t, os.Chmod(path, 0644))
+		_, err := cache.Load("github.com")
+		assert.ErrorContains(t, err, "accessible by other users")
+	})
+}
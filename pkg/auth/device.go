@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// slowDownIncrement is how much the polling interval grows each time GitHub
+// answers with a slow_down error, as required by the device flow spec.
+const slowDownIncrement = 5 * time.Second
+
+// DeviceFlow runs the OAuth device authorization flow against a GitHub host.
+// See https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/authorizing-oauth-apps#device-flow
+type DeviceFlow struct {
+	// WebURL is the base URL of the GitHub web host, e.g. https://github.com/
+	WebURL *url.URL
+	// ClientID of the OAuth or GitHub App with device flow enabled
+	ClientID string
+	// Scopes to request for the token
+	Scopes []string
+	// HTTPClient used to talk to GitHub, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// DeviceCode is the response to a device code request. UserCode must be entered
+// by the user at VerificationURI before the code expires.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// AccessToken is the token returned once the user has authorized the device.
+type AccessToken struct {
+	Token string `json:"access_token"`
+	Type  string `json:"token_type"`
+	Scope string `json:"scope"`
+}
+
+// RequestCode starts the flow by requesting a device and user verification code.
+func (f *DeviceFlow) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{
+		"client_id": {f.ClientID},
+		"scope":     {strings.Join(f.Scopes, " ")},
+	}
+
+	var code struct {
+		DeviceCode
+		oauthErrorResponse
+	}
+	if err := f.post(ctx, "login/device/code", form, &code); err != nil {
+		return nil, fmt.Errorf("failed to request device code: %w", err)
+	}
+	if code.Error != "" {
+		return nil, fmt.Errorf("failed to request device code: %s", code.oauthErrorResponse)
+	}
+	return &code.DeviceCode, nil
+}
+
+// PollToken polls GitHub until the user has authorized the device, the code
+// expires, or ctx is done.
+func (f *DeviceFlow) PollToken(ctx context.Context, code *DeviceCode) (*AccessToken, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*time.Second)
+	defer cancel()
+
+	form := url.Values{
+		"client_id":   {f.ClientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("device authorization was not completed: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		var token struct {
+			AccessToken
+			oauthErrorResponse
+		}
+		if err := f.post(ctx, "login/oauth/access_token", form, &token); err != nil {
+			return nil, fmt.Errorf("failed to poll for access token: %w", err)
+		}
+
+		switch token.Error {
+		case "":
+			if token.Token == "" {
+				return nil, errors.New("failed to poll for access token: response did not contain a token")
+			}
+			return &token.AccessToken, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += slowDownIncrement
+			continue
+		default:
+			return nil, fmt.Errorf("device authorization failed: %s", token.oauthErrorResponse)
+		}
+	}
+}
+
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, v any) error {
+	endpoint := f.WebURL.ResolveReference(&url.URL{Path: path})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := f.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, endpoint)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// oauthErrorResponse is the error payload GitHub returns with a 200 status from
+// the OAuth endpoints.
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (e oauthErrorResponse) String() string {
+	if e.ErrorDescription != "" {
+		return fmt.Sprintf("%s: %s", e.Error, e.ErrorDescription)
+	}
+	return e.Error
+}
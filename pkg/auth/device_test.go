@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeviceFlow(t *testing.T) {
+	var polls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "repo read:org", r.PostForm.Get("scope"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":0}`))
+	})
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "dc", r.PostForm.Get("device_code"))
+		polls++
+		if polls < 3 {
+			_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"gho_token","token_type":"bearer","scope":"repo,read:org"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	webURL, _ := url.Parse(server.URL + "/")
+	flow := &DeviceFlow{WebURL: webURL, ClientID: "client-id", Scopes: []string{"repo", "read:org"}, HTTPClient: server.Client()}
+
+	code, err := flow.RequestCode(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD-1234", code.UserCode)
+
+	token, err := flow.PollToken(context.Background(), code)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_token", token.Token)
+	assert.Equal(t, "repo,read:org", token.Scope)
+	assert.Equal(t, 3, polls)
+}
+
+func Test_DeviceFlowDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"access_denied","error_description":"The user has denied your application access."}`))
+	}))
+	defer server.Close()
+
+	webURL, _ := url.Parse(server.URL + "/")
+	flow := &DeviceFlow{WebURL: webURL, ClientID: "client-id", HTTPClient: server.Client()}
+
+	_, err := flow.PollToken(context.Background(), &DeviceCode{DeviceCode: "dc", ExpiresIn: 60})
+	assert.ErrorContains(t, err, "access_denied")
+}
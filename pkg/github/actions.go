@@ -30,6 +30,7 @@ func ListWorkflows(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_WORKFLOWS_USER_TITLE", "List workflows"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -90,6 +91,7 @@ func ListWorkflowRuns(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_WORKFLOW_RUNS_USER_TITLE", "List workflow runs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -229,6 +231,7 @@ func RunWorkflow(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				Title:        t("TOOL_RUN_WORKFLOW_USER_TITLE", "Run workflow"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"repo"}, Permissions: []string{"actions:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -328,6 +331,7 @@ func GetWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_GET_WORKFLOW_RUN_USER_TITLE", "Get workflow run"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -384,6 +388,7 @@ func GetWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_GET_WORKFLOW_RUN_LOGS_USER_TITLE", "Get workflow run logs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -450,6 +455,7 @@ func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_WORKFLOW_JOBS_USER_TITLE", "List workflow jobs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -531,13 +537,15 @@ func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFun
 }
 
 // GetJobLogs creates a tool to download logs for a specific workflow job or efficiently get all failed job logs for a workflow run
-func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, contentWindowSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+// Log content is downloaded with logClient, or http.DefaultClient when it is nil.
+func GetJobLogs(getClient GetClientFn, logClient *http.Client, t translations.TranslationHelperFunc, contentWindowSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_job_logs",
 			mcp.WithDescription(t("TOOL_GET_JOB_LOGS_DESCRIPTION", "Download logs for a specific workflow job or efficiently get all failed job logs for a workflow run")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        t("TOOL_GET_JOB_LOGS_USER_TITLE", "Get job logs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -614,10 +622,10 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 
 			if failedOnly && runID > 0 {
 				// Handle failed-only mode: get logs for all failed jobs in the workflow run
-				return handleFailedJobLogs(ctx, client, owner, repo, int64(runID), returnContent, tailLines, contentWindowSize)
+				return handleFailedJobLogs(ctx, client, logClient, owner, repo, int64(runID), returnContent, tailLines, contentWindowSize)
 			} else if jobID > 0 {
 				// Handle single job mode
-				return handleSingleJobLogs(ctx, client, owner, repo, int64(jobID), returnContent, tailLines, contentWindowSize)
+				return handleSingleJobLogs(ctx, client, logClient, owner, repo, int64(jobID), returnContent, tailLines, contentWindowSize)
 			}
 
 			return mcp.NewToolResultError("Either job_id must be provided for single job logs, or run_id with failed_only=true for failed job logs"), nil
@@ -625,7 +633,7 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 }
 
 // handleFailedJobLogs gets logs for all failed jobs in a workflow run
-func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
+func handleFailedJobLogs(ctx context.Context, client *github.Client, logClient *http.Client, owner, repo string, runID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
 	// First, get all jobs for the workflow run
 	jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{
 		Filter: "latest",
@@ -657,7 +665,7 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 	// Collect logs for all failed jobs
 	var logResults []map[string]any
 	for _, job := range failedJobs {
-		jobResult, resp, err := getJobLogData(ctx, client, owner, repo, job.GetID(), job.GetName(), returnContent, tailLines, contentWindowSize)
+		jobResult, resp, err := getJobLogData(ctx, client, logClient, owner, repo, job.GetID(), job.GetName(), returnContent, tailLines, contentWindowSize)
 		if err != nil {
 			// Continue with other jobs even if one fails
 			jobResult = map[string]any{
@@ -690,8 +698,8 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 }
 
 // handleSingleJobLogs gets logs for a single job
-func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo string, jobID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
-	jobResult, resp, err := getJobLogData(ctx, client, owner, repo, jobID, "", returnContent, tailLines, contentWindowSize)
+func handleSingleJobLogs(ctx context.Context, client *github.Client, logClient *http.Client, owner, repo string, jobID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
+	jobResult, resp, err := getJobLogData(ctx, client, logClient, owner, repo, jobID, "", returnContent, tailLines, contentWindowSize)
 	if err != nil {
 		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get job logs", resp, err), nil
 	}
@@ -705,7 +713,7 @@ func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo
 }
 
 // getJobLogData retrieves log data for a single job, either as URL or content
-func getJobLogData(ctx context.Context, client *github.Client, owner, repo string, jobID int64, jobName string, returnContent bool, tailLines int, contentWindowSize int) (map[string]any, *github.Response, error) {
+func getJobLogData(ctx context.Context, client *github.Client, logClient *http.Client, owner, repo string, jobID int64, jobName string, returnContent bool, tailLines int, contentWindowSize int) (map[string]any, *github.Response, error) {
 	// Get the download URL for the job logs
 	url, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, 1)
 	if err != nil {
@@ -722,7 +730,7 @@ func getJobLogData(ctx context.Context, client *github.Client, owner, repo strin
 
 	if returnContent {
 		// Download and return the actual log content
-		content, originalLength, httpResp, err := downloadLogContent(ctx, url.String(), tailLines, contentWindowSize) //nolint:bodyclose // Response body is closed in downloadLogContent, but we need to return httpResp
+		content, originalLength, httpResp, err := downloadLogContent(ctx, logClient, url.String(), tailLines, contentWindowSize) //nolint:bodyclose // Response body is closed in downloadLogContent, but we need to return httpResp
 		if err != nil {
 			// To keep the return value consistent wrap the response as a GitHub Response
 			ghRes := &github.Response{
@@ -743,11 +751,18 @@ func getJobLogData(ctx context.Context, client *github.Client, owner, repo strin
 	return result, resp, nil
 }
 
-func downloadLogContent(ctx context.Context, logURL string, tailLines int, maxLines int) (string, int, *http.Response, error) {
+func downloadLogContent(ctx context.Context, client *http.Client, logURL string, tailLines int, maxLines int) (string, int, *http.Response, error) {
 	prof := profiler.New(nil, profiler.IsProfilingEnabled())
 	finish := prof.Start(ctx, "log_buffer_processing")
 
-	httpResp, err := http.Get(logURL) //nolint:gosec
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL, nil)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("failed to download logs: %w", err)
+	}
+	httpResp, err := client.Do(req) //nolint:gosec
 	if err != nil {
 		return "", 0, httpResp, fmt.Errorf("failed to download logs: %w", err)
 	}
@@ -786,6 +801,7 @@ func RerunWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_RERUN_WORKFLOW_RUN_USER_TITLE", "Rerun workflow run"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"repo"}, Permissions: []string{"actions:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -849,6 +865,7 @@ func RerunFailedJobs(getClient GetClientFn, t translations.TranslationHelperFunc
 				Title:        t("TOOL_RERUN_FAILED_JOBS_USER_TITLE", "Rerun failed jobs"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"repo"}, Permissions: []string{"actions:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -909,9 +926,11 @@ func CancelWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFu
 	return mcp.NewTool("cancel_workflow_run",
 			mcp.WithDescription(t("TOOL_CANCEL_WORKFLOW_RUN_DESCRIPTION", "Cancel a workflow run")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_CANCEL_WORKFLOW_RUN_USER_TITLE", "Cancel workflow run"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_CANCEL_WORKFLOW_RUN_USER_TITLE", "Cancel workflow run"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"repo"}, Permissions: []string{"actions:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -977,6 +996,7 @@ func ListWorkflowRunArtifacts(getClient GetClientFn, t translations.TranslationH
 				Title:        t("TOOL_LIST_WORKFLOW_RUN_ARTIFACTS_USER_TITLE", "List workflow artifacts"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1046,6 +1066,7 @@ func DownloadWorkflowRunArtifact(getClient GetClientFn, t translations.Translati
 				Title:        t("TOOL_DOWNLOAD_WORKFLOW_RUN_ARTIFACT_USER_TITLE", "Download workflow artifact"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1112,6 +1133,7 @@ func DeleteWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelp
 				ReadOnlyHint:    ToBoolPtr(false),
 				DestructiveHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"repo"}, Permissions: []string{"actions:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1175,6 +1197,7 @@ func GetWorkflowRunUsage(getClient GetClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_GET_WORKFLOW_RUN_USAGE_USER_TITLE", "Get workflow usage"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"actions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
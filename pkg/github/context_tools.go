@@ -41,6 +41,8 @@ func GetMe(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Too
 			Title:        t("TOOL_GET_ME_USER_TITLE", "Get my user profile"),
 			ReadOnlyHint: ToBoolPtr(true),
 		}),
+		WithTokenRequirements(TokenRequirements{UserOnly: true}),
+		WithoutParameters(),
 	)
 
 	type args struct{}
@@ -113,6 +115,7 @@ func GetTeams(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations
 				Title:        t("TOOL_GET_TEAMS_TITLE", "Get teams"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:org"}, Permissions: []string{"members:read"}}),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			user, err := OptionalParam[string](request, "user")
@@ -205,6 +208,7 @@ func GetTeamMembers(getGQLClient GetGQLClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_GET_TEAM_MEMBERS_TITLE", "Get team members"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:org"}, Permissions: []string{"members:read"}}),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			org, err := RequiredParam[string](request, "org")
@@ -124,6 +124,7 @@ func ListDiscussions(getGQLClient GetGQLClientFn, t translations.TranslationHelp
 				Title:        t("TOOL_LIST_DISCUSSIONS_USER_TITLE", "List discussions"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"discussions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -263,6 +264,7 @@ func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_GET_DISCUSSION_USER_TITLE", "Get discussion"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"discussions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -302,6 +304,10 @@ func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelper
 						Category  struct {
 							Name githubv4.String
 						} `graphql:"category"`
+						Author struct {
+							Login githubv4.String
+						}
+						AuthorAssociation githubv4.String
 					} `graphql:"discussion(number: $discussionNumber)"`
 				} `graphql:"repository(owner: $owner, name: $repo)"`
 			}
@@ -323,7 +329,11 @@ func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelper
 				DiscussionCategory: &github.DiscussionCategory{
 					Name: github.Ptr(string(d.Category.Name)),
 				},
+				User:              &github.User{Login: github.Ptr(string(d.Author.Login))},
+				AuthorAssociation: github.Ptr(string(d.AuthorAssociation)),
 			}
+			LockdownFromContext(ctx).withhold(ctx, params.Owner, params.Repo, discussion.User, discussion.AuthorAssociation, &discussion.Title, &discussion.Body)
+
 			out, err := json.Marshal(discussion)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal discussion: %w", err)
@@ -340,6 +350,7 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 				Title:        t("TOOL_GET_DISCUSSION_COMMENTS_USER_TITLE", "Get discussion comments"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"discussions:read"}}),
 			mcp.WithString("owner", mcp.Required(), mcp.Description("Repository owner")),
 			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository name")),
 			mcp.WithNumber("discussionNumber", mcp.Required(), mcp.Description("Discussion Number")),
@@ -387,7 +398,11 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 					Discussion struct {
 						Comments struct {
 							Nodes []struct {
-								Body githubv4.String
+								Body   githubv4.String
+								Author struct {
+									Login githubv4.String
+								}
+								AuthorAssociation githubv4.String
 							}
 							PageInfo struct {
 								HasNextPage     githubv4.Boolean
@@ -415,9 +430,16 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			lockdown := LockdownFromContext(ctx)
 			var comments []*github.IssueComment
 			for _, c := range q.Repository.Discussion.Comments.Nodes {
-				comments = append(comments, &github.IssueComment{Body: github.Ptr(string(c.Body))})
+				comment := &github.IssueComment{
+					Body:              github.Ptr(string(c.Body)),
+					User:              &github.User{Login: github.Ptr(string(c.Author.Login))},
+					AuthorAssociation: github.Ptr(string(c.AuthorAssociation)),
+				}
+				lockdown.withhold(ctx, params.Owner, params.Repo, comment.User, comment.AuthorAssociation, &comment.Body)
+				comments = append(comments, comment)
 			}
 
 			// Create response with pagination info
@@ -448,6 +470,7 @@ func ListDiscussionCategories(getGQLClient GetGQLClientFn, t translations.Transl
 				Title:        t("TOOL_LIST_DISCUSSION_CATEGORIES_USER_TITLE", "List discussion categories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"discussions:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -484,7 +484,7 @@ func Test_GetDiscussion(t *testing.T) {
 	assert.ElementsMatch(t, toolDef.InputSchema.Required, []string{"owner", "repo", "discussionNumber"})
 
 	// Use exact string query that matches implementation output
-	qGetDiscussion := "query($discussionNumber:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){number,title,body,createdAt,url,category{name}}}}"
+	qGetDiscussion := "query($discussionNumber:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){number,title,body,createdAt,url,category{name},author{login},authorAssociation}}}"
 
 	vars := map[string]interface{}{
 		"owner":            "owner",
@@ -508,6 +508,7 @@ func Test_GetDiscussion(t *testing.T) {
 					"url":       "https://github.com/owner/repo/discussions/1",
 					"createdAt": "2025-04-25T12:00:00Z",
 					"category":  map[string]any{"name": "General"},
+					"author":    map[string]any{"login": "octocat"},
 				}},
 			}),
 			expectError: false,
@@ -553,6 +554,7 @@ func Test_GetDiscussion(t *testing.T) {
 			assert.Equal(t, *tc.expected.Number, *out.Number)
 			assert.Equal(t, *tc.expected.Title, *out.Title)
 			assert.Equal(t, *tc.expected.Body, *out.Body)
+			assert.Equal(t, "octocat", out.User.GetLogin())
 			// Check category label
 			assert.Equal(t, *tc.expected.DiscussionCategory.Name, *out.DiscussionCategory.Name)
 		})
@@ -570,7 +572,7 @@ func Test_GetDiscussionComments(t *testing.T) {
 	assert.ElementsMatch(t, toolDef.InputSchema.Required, []string{"owner", "repo", "discussionNumber"})
 
 	// Use exact string query that matches implementation output
-	qGetComments := "query($after:String$discussionNumber:Int!$first:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){comments(first: $first, after: $after){nodes{body},pageInfo{hasNextPage,hasPreviousPage,startCursor,endCursor},totalCount}}}}"
+	qGetComments := "query($after:String$discussionNumber:Int!$first:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){comments(first: $first, after: $after){nodes{body,author{login},authorAssociation},pageInfo{hasNextPage,hasPreviousPage,startCursor,endCursor},totalCount}}}}"
 
 	// Variables matching what GraphQL receives after JSON marshaling/unmarshaling
 	vars := map[string]interface{}{
@@ -586,8 +588,8 @@ func Test_GetDiscussionComments(t *testing.T) {
 			"discussion": map[string]any{
 				"comments": map[string]any{
 					"nodes": []map[string]any{
-						{"body": "This is the first comment"},
-						{"body": "This is the second comment"},
+						{"body": "This is the first comment", "author": map[string]any{"login": "octocat"}},
+						{"body": "This is the second comment", "author": map[string]any{"login": "hubot"}},
 					},
 					"pageInfo": map[string]any{
 						"hasNextPage":     false,
@@ -635,6 +637,7 @@ func Test_GetDiscussionComments(t *testing.T) {
 	for i, comment := range response.Comments {
 		assert.Equal(t, expectedBodies[i], *comment.Body)
 	}
+	assert.Equal(t, "hubot", response.Comments[1].User.GetLogin())
 }
 
 func Test_ListDiscussionCategories(t *testing.T) {
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
 
 	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -16,9 +20,15 @@ func ToolsetEnum(toolsetGroup *toolsets.ToolsetGroup) mcp.PropertyOption {
 	for name := range toolsetGroup.Toolsets {
 		toolsetNames = append(toolsetNames, name)
 	}
+	// Sorted so that the tool definitions are the same each time they're built
+	sort.Strings(toolsetNames)
 	return mcp.Enum(toolsetNames...)
 }
 
+// EnableToolset creates a tool that enables a toolset. For clients whose sessions
+// can have tools of their own, the toolset is only enabled for the calling
+// session, and only that session is told that its tool list changed. Otherwise
+// the toolset is enabled for all sessions.
 func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("enable_toolset",
 			mcp.WithDescription(t("TOOL_ENABLE_TOOLSET_DESCRIPTION", "Enable one of the sets of tools the GitHub MCP server provides, use get_toolset_tools and list_available_toolsets first to see what this will enable")),
@@ -33,7 +43,7 @@ func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t t
 				ToolsetEnum(toolsetGroup),
 			),
 		),
-		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// We need to convert the toolsets back to a map for JSON serialization
 			toolsetName, err := RequiredParam[string](request, "toolset")
 			if err != nil {
@@ -43,22 +53,109 @@ func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t t
 			if toolset == nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
 			}
-			if toolset.Enabled {
+			if toolsetEnabled(ctx, toolset) {
 				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is already enabled", toolsetName)), nil
 			}
 
-			toolset.Enabled = true
-
-			// caution: this currently affects the global tools and notifies all clients:
-			//
-			// Send notification to all initialized sessions
-			// s.sendNotificationToAllClients("notifications/tools/list_changed", nil)
-			s.AddTools(toolset.GetActiveTools()...)
+			if err := enableToolset(ctx, s, toolset); err != nil {
+				return nil, err
+			}
 
 			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s enabled", toolsetName)), nil
 		}
 }
 
+// enableToolset enables toolset for the session of ctx if it can have tools of
+// its own, and for all sessions otherwise.
+func enableToolset(ctx context.Context, s *server.MCPServer, toolset *toolsets.Toolset) error {
+	if session, ok := sessionWithTools(ctx); ok {
+		if err := s.AddSessionTools(session.SessionID(), toolset.GetAvailableTools()...); err != nil {
+			return fmt.Errorf("failed to enable toolset %s: %w", toolset.Name, err)
+		}
+		return nil
+	}
+
+	toolset.Enabled = true
+	s.AddTools(toolset.GetActiveTools()...)
+	return nil
+}
+
+// DisableToolset creates a tool that disables a toolset again, for the calling
+// session when the toolset was enabled for it alone, otherwise for all sessions.
+func DisableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("disable_toolset",
+			mcp.WithDescription(t("TOOL_DISABLE_TOOLSET_DESCRIPTION", "Disable a toolset enabled with enable_toolset once its tools are no longer needed, to keep the list of tools short")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title: t("TOOL_DISABLE_TOOLSET_USER_TITLE", "Disable a toolset"),
+				// Not modifying GitHub data so no need to show a warning
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("toolset",
+				mcp.Required(),
+				mcp.Description("The name of the toolset to disable"),
+				ToolsetEnum(toolsetGroup),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			toolsetName, err := RequiredParam[string](request, "toolset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			toolset := toolsetGroup.Toolsets[toolsetName]
+			if toolset == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
+			}
+
+			names := make([]string, 0)
+			for _, tool := range toolset.GetAvailableTools() {
+				names = append(names, tool.Tool.Name)
+			}
+
+			session, hasSession := sessionWithTools(ctx)
+			switch {
+			case hasSession && sessionHasToolset(session, toolset):
+				if err := s.DeleteSessionTools(session.SessionID(), names...); err != nil {
+					return nil, fmt.Errorf("failed to disable toolset %s: %w", toolsetName, err)
+				}
+			case hasSession && toolset.Enabled:
+				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s is enabled for all sessions and can't be disabled for this one", toolsetName)), nil
+			case toolset.Enabled:
+				toolset.Enabled = false
+				s.DeleteTools(names...)
+			default:
+				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is not enabled", toolsetName)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s disabled", toolsetName)), nil
+		}
+}
+
+// sessionWithTools returns the session of ctx if it can have tools of its own.
+func sessionWithTools(ctx context.Context) (server.SessionWithTools, bool) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithTools)
+	return session, ok
+}
+
+// sessionHasToolset reports whether the tools of toolset were added to session.
+func sessionHasToolset(session server.SessionWithTools, toolset *toolsets.Toolset) bool {
+	sessionTools := session.GetSessionTools()
+	for _, tool := range toolset.GetAvailableTools() {
+		if _, ok := sessionTools[tool.Tool.Name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toolsetEnabled reports whether toolset is enabled for all sessions or for the session of ctx.
+func toolsetEnabled(ctx context.Context, toolset *toolsets.Toolset) bool {
+	if toolset.Enabled {
+		return true
+	}
+	session, ok := sessionWithTools(ctx)
+	return ok && sessionHasToolset(session, toolset)
+}
+
 func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_available_toolsets",
 			mcp.WithDescription(t("TOOL_LIST_AVAILABLE_TOOLSETS_DESCRIPTION", "List all available toolsets this GitHub MCP server can offer, providing the enabled status of each. Use this when a task could be achieved with a GitHub tool and the currently available tools aren't enough. Call get_toolset_tools with these toolset names to discover specific tools you can call")),
@@ -67,7 +164,7 @@ func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.T
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
 		),
-		func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// We need to convert the toolsetGroup back to a map for JSON serialization
 
 			payload := []map[string]string{}
@@ -78,7 +175,7 @@ func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.T
 						"name":              name,
 						"description":       ts.Description,
 						"can_enable":        "true",
-						"currently_enabled": fmt.Sprintf("%t", ts.Enabled),
+						"currently_enabled": fmt.Sprintf("%t", toolsetEnabled(ctx, ts)),
 					}
 					payload = append(payload, t)
 				}
@@ -136,3 +233,218 @@ func GetToolsetsTools(toolsetGroup *toolsets.ToolsetGroup, t translations.Transl
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// FindTools creates a tool that searches the tools of all toolsets for those
+// matching a query, so that the right tool can be found without listing the
+// tools of every toolset first.
+func FindTools(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("find_tools",
+			mcp.WithDescription(t("TOOL_FIND_TOOLS_DESCRIPTION", "Find the tools best suited to a task across all toolsets, ranked by relevance to the query, with the toolset each belongs to and its parameters. Prefer this over list_available_toolsets and get_toolset_tools when you know what you want to do")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title: t("TOOL_FIND_TOOLS_USER_TITLE", "Find tools"),
+				// Not modifying GitHub data so no need to show a warning
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("What you want to do, e.g. 'list the open pull requests of a repository'"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of tools to return"),
+				mcp.DefaultNumber(10),
+				mcp.Min(1),
+				mcp.Max(50),
+			),
+			mcp.WithBoolean("enable",
+				mcp.Description("Enable the toolsets of the tools found, so that they can be called right away"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			query, err := RequiredParam[string](request, "query")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			limit, err := OptionalIntParamWithDefault(request, "limit", 10)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			enable, err := OptionalParam[bool](request, "enable")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(searchTerms(query)) == 0 {
+				return mcp.NewToolResultError("query must contain at least one word"), nil
+			}
+
+			matches := searchTools(toolsetGroup, query)
+			if len(matches) == 0 {
+				return mcp.NewToolResultText("No tools match the query, use list_available_toolsets to browse the toolsets instead"), nil
+			}
+			if limit > 0 && len(matches) > limit {
+				matches = matches[:limit]
+			}
+
+			payload := make([]map[string]any, 0, len(matches))
+			for _, match := range matches {
+				toolset := toolsetGroup.Toolsets[match.toolset]
+				if enable && !toolsetEnabled(ctx, toolset) {
+					if err := enableToolset(ctx, s, toolset); err != nil {
+						return nil, err
+					}
+				}
+				payload = append(payload, map[string]any{
+					"name":        match.tool.Name,
+					"description": match.tool.Description,
+					"toolset":     match.toolset,
+					"parameters":  parameterSummary(match.tool),
+					"enabled":     toolsetEnabled(ctx, toolset),
+				})
+			}
+
+			r, err := json.Marshal(payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal tools: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// Weights of the parts of a tool when searching for tools. The name says the
+// most about what a tool does, its description the least.
+const (
+	nameWeight        = 3
+	parameterWeight   = 2
+	descriptionWeight = 1
+)
+
+// searchStopWords are left out of search terms, as they say nothing about a tool.
+var searchStopWords = map[string]bool{
+	"a": true, "all": true, "an": true, "and": true, "are": true, "be": true, "by": true, "can": true,
+	"for": true, "from": true, "in": true, "into": true, "is": true, "it": true, "me": true, "my": true,
+	"of": true, "on": true, "or": true, "the": true, "this": true, "to": true, "with": true,
+}
+
+type toolMatch struct {
+	tool    mcp.Tool
+	toolset string
+	score   float64
+}
+
+// searchTools ranks the available tools of all toolsets by how well they match
+// query, weighting the frequency of each query term in a tool by how rare the
+// term is across tools. Frequencies are dampened, so that tools with long
+// descriptions don't win by repetition. Tools matching none of the terms are
+// left out.
+func searchTools(toolsetGroup *toolsets.ToolsetGroup, query string) []toolMatch {
+	type document struct {
+		match       toolMatch
+		frequencies map[string]float64
+	}
+	var documents []document
+	documentFrequencies := map[string]int{}
+	for name, toolset := range toolsetGroup.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			frequencies := toolTermFrequencies(tool.Tool)
+			for term := range frequencies {
+				documentFrequencies[term]++
+			}
+			documents = append(documents, document{match: toolMatch{tool: tool.Tool, toolset: name}, frequencies: frequencies})
+		}
+	}
+
+	terms := map[string]bool{}
+	for _, term := range searchTerms(query) {
+		terms[term] = true
+	}
+
+	var matches []toolMatch
+	for _, document := range documents {
+		for term := range terms {
+			if frequency := document.frequencies[term]; frequency > 0 {
+				idf := math.Log(1 + float64(len(documents))/float64(documentFrequencies[term]))
+				document.match.score += math.Log(1+frequency) * idf
+			}
+		}
+		if document.match.score > 0 {
+			matches = append(matches, document.match)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].tool.Name < matches[j].tool.Name
+	})
+	return matches
+}
+
+// toolTermFrequencies counts the search terms in the name, parameter names and
+// description of tool, according to the weight of each.
+func toolTermFrequencies(tool mcp.Tool) map[string]float64 {
+	frequencies := map[string]float64{}
+	add := func(text string, weight float64) {
+		for _, term := range searchTerms(text) {
+			frequencies[term] += weight
+		}
+	}
+	add(tool.Name, nameWeight)
+	for name := range tool.InputSchema.Properties {
+		add(name, parameterWeight)
+	}
+	add(tool.Description, descriptionWeight)
+	return frequencies
+}
+
+// searchTerms splits text into lowercase words, splitting identifiers such as
+// pull_request or pullNumber too. Plurals are reduced to the singular, roughly,
+// so that "issues" matches "issue".
+func searchTerms(text string) []string {
+	var terms []string
+	var word []rune
+	flush := func() {
+		term := strings.ToLower(string(word))
+		word = word[:0]
+		if len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") {
+			term = strings.TrimSuffix(term, "s")
+		}
+		if term != "" && !searchStopWords[term] {
+			terms = append(terms, term)
+		}
+	}
+	for i, r := range text {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(word) > 0 && unicode.IsLower(word[len(word)-1]):
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return terms
+}
+
+// parameterSummary describes the parameters of tool by their type, and whether
+// they are required, to keep search results short.
+func parameterSummary(tool mcp.Tool) map[string]string {
+	required := map[string]bool{}
+	for _, name := range tool.InputSchema.Required {
+		required[name] = true
+	}
+	parameters := make(map[string]string, len(tool.InputSchema.Properties))
+	for name, property := range tool.InputSchema.Properties {
+		schema, _ := property.(map[string]any)
+		summary, _ := schema["type"].(string)
+		if summary == "" {
+			summary = "any"
+		}
+		if required[name] {
+			summary += ", required"
+		}
+		parameters[name] = summary
+	}
+	return parameters
+}
@@ -0,0 +1,191 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolsSession is a session that, like those of the HTTP transports, can have tools of its own.
+type toolsSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	mu            sync.Mutex
+	tools         map[string]server.ServerTool
+}
+
+func newToolsSession(id string) *toolsSession {
+	return &toolsSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (s *toolsSession) SessionID() string                                   { return s.id }
+func (s *toolsSession) Initialize()                                         {}
+func (s *toolsSession) Initialized() bool                                   { return true }
+func (s *toolsSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+
+func (s *toolsSession) GetSessionTools() map[string]server.ServerTool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tools
+}
+
+func (s *toolsSession) SetSessionTools(tools map[string]server.ServerTool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = tools
+}
+
+func Test_DynamicToolsets(t *testing.T) {
+	newToolsets := func() (*server.MCPServer, *toolsets.ToolsetGroup) {
+		s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+		tsg := toolsets.NewToolsetGroup(false)
+		tsg.AddToolset(toolsets.NewToolset("issues", "GitHub Issues related tools").
+			AddReadTools(toolsets.NewServerTool(GetIssue(nil, translations.NullTranslationHelper))))
+		return s, tsg
+	}
+	callTool := func(ctx context.Context, tool mcp.Tool, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+		request := createMCPRequest(args)
+		request.Params.Name = tool.Name
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+		return result
+	}
+	currentlyEnabled := func(ctx context.Context, tsg *toolsets.ToolsetGroup) string {
+		tool, handler := ListAvailableToolsets(tsg, translations.NullTranslationHelper)
+		var payload []map[string]string
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, callTool(ctx, tool, handler, map[string]any{})).Text), &payload))
+		require.Len(t, payload, 1)
+		return payload[0]["currently_enabled"]
+	}
+
+	t.Run("per session", func(t *testing.T) {
+		s, tsg := newToolsets()
+		enableTool, enable := EnableToolset(s, tsg, translations.NullTranslationHelper)
+		disableTool, disable := DisableToolset(s, tsg, translations.NullTranslationHelper)
+
+		first, second := newToolsSession("first"), newToolsSession("second")
+		require.NoError(t, s.RegisterSession(context.Background(), first))
+		require.NoError(t, s.RegisterSession(context.Background(), second))
+		firstCtx := s.WithContext(context.Background(), first)
+		secondCtx := s.WithContext(context.Background(), second)
+
+		result := callTool(firstCtx, enableTool, enable, map[string]any{"toolset": "issues"})
+		assert.Equal(t, "Toolset issues enabled", getTextResult(t, result).Text)
+		assert.False(t, tsg.Toolsets["issues"].Enabled, "other sessions are unaffected")
+		assert.Contains(t, first.GetSessionTools(), "get_issue")
+		assert.Empty(t, second.GetSessionTools())
+		assert.Equal(t, "true", currentlyEnabled(firstCtx, tsg))
+		assert.Equal(t, "false", currentlyEnabled(secondCtx, tsg))
+
+		require.Len(t, first.notifications, 1)
+		assert.Equal(t, "notifications/tools/list_changed", (<-first.notifications).Method)
+		assert.Empty(t, second.notifications, "only the session enabling the toolset is notified")
+
+		result = callTool(firstCtx, enableTool, enable, map[string]any{"toolset": "issues"})
+		assert.Equal(t, "Toolset issues is already enabled", getTextResult(t, result).Text)
+
+		result = callTool(secondCtx, disableTool, disable, map[string]any{"toolset": "issues"})
+		assert.Equal(t, "Toolset issues is not enabled", getTextResult(t, result).Text)
+
+		result = callTool(firstCtx, disableTool, disable, map[string]any{"toolset": "issues"})
+		assert.Equal(t, "Toolset issues disabled", getTextResult(t, result).Text)
+		assert.NotContains(t, first.GetSessionTools(), "get_issue")
+		assert.Equal(t, "false", currentlyEnabled(firstCtx, tsg))
+		assert.Len(t, first.notifications, 1)
+	})
+
+	t.Run("toolsets enabled for all sessions", func(t *testing.T) {
+		s, tsg := newToolsets()
+		require.NoError(t, tsg.EnableToolset("issues"))
+		disableTool, disable := DisableToolset(s, tsg, translations.NullTranslationHelper)
+
+		session := newToolsSession("session")
+		require.NoError(t, s.RegisterSession(context.Background(), session))
+		result := callTool(s.WithContext(context.Background(), session), disableTool, disable, map[string]any{"toolset": "issues"})
+		assert.Equal(t, "Toolset issues is enabled for all sessions and can't be disabled for this one", getErrorResult(t, result).Text)
+		assert.True(t, tsg.Toolsets["issues"].Enabled)
+	})
+
+	t.Run("sessions without tools of their own", func(t *testing.T) {
+		s, tsg := newToolsets()
+		enableTool, enable := EnableToolset(s, tsg, translations.NullTranslationHelper)
+		disableTool, disable := DisableToolset(s, tsg, translations.NullTranslationHelper)
+
+		callTool(context.Background(), enableTool, enable, map[string]any{"toolset": "issues"})
+		assert.True(t, tsg.Toolsets["issues"].Enabled)
+		assert.NotNil(t, s.GetTool("get_issue"))
+
+		callTool(context.Background(), disableTool, disable, map[string]any{"toolset": "issues"})
+		assert.False(t, tsg.Toolsets["issues"].Enabled)
+		assert.Nil(t, s.GetTool("get_issue"))
+	})
+
+	t.Run("unknown toolset", func(t *testing.T) {
+		s, tsg := newToolsets()
+		disableTool, disable := DisableToolset(s, tsg, translations.NullTranslationHelper)
+		result := callTool(context.Background(), disableTool, disable, map[string]any{"toolset": "wiki"})
+		assert.Equal(t, "Toolset wiki not found", getErrorResult(t, result).Text)
+	})
+}
+
+func Test_FindTools(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	tsg := toolsets.NewToolsetGroup(false)
+	tsg.AddToolset(toolsets.NewToolset("issues", "GitHub Issues related tools").
+		AddReadTools(toolsets.NewServerTool(GetIssue(nil, translations.NullTranslationHelper))).
+		AddWriteTools(toolsets.NewServerTool(CreateIssue(nil, translations.NullTranslationHelper))))
+	tsg.AddToolset(toolsets.NewToolset("pull_requests", "GitHub Pull Request related tools").
+		AddWriteTools(toolsets.NewServerTool(MergePullRequest(nil, translations.NullTranslationHelper))))
+
+	tool, handler := FindTools(s, tsg, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	find := func(args map[string]any) []map[string]any {
+		request := createMCPRequest(args)
+		request.Params.Name = tool.Name
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		var found []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &found))
+		return found
+	}
+
+	found := find(map[string]any{"query": "Merge the pull requests"})
+	require.NotEmpty(t, found)
+	assert.Equal(t, "merge_pull_request", found[0]["name"])
+	assert.Equal(t, "pull_requests", found[0]["toolset"])
+	assert.Equal(t, "number, required", found[0]["parameters"].(map[string]any)["pullNumber"])
+	assert.Equal(t, false, found[0]["enabled"])
+
+	found = find(map[string]any{"query": "create a new issue", "limit": float64(1)})
+	require.Len(t, found, 1)
+	assert.Equal(t, "create_issue", found[0]["name"])
+
+	found = find(map[string]any{"query": "get issue details", "enable": true})
+	assert.Equal(t, "get_issue", found[0]["name"])
+	assert.Equal(t, true, found[0]["enabled"])
+	assert.True(t, tsg.Toolsets["issues"].Enabled)
+	assert.NotNil(t, s.GetTool("get_issue"))
+
+	request := createMCPRequest(map[string]any{"query": "zebra"})
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.Contains(t, getTextResult(t, result).Text, "No tools match the query")
+
+	result, err = handler(context.Background(), createMCPRequest(map[string]any{"query": "the"}))
+	require.NoError(t, err)
+	assert.Equal(t, "query must contain at least one word", getErrorResult(t, result).Text)
+}
+
+func Test_searchTerms(t *testing.T) {
+	assert.Equal(t, []string{"list", "pull", "request", "pull", "number", "status"}, searchTerms("List pull_requests: pullNumber, status"))
+}
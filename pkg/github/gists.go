@@ -21,6 +21,7 @@ func ListGists(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				Title:        t("TOOL_LIST_GISTS", "List Gists"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{UserOnly: true}),
 			mcp.WithString("username",
 				mcp.Description("GitHub username (omit for authenticated user's gists)"),
 			),
@@ -97,6 +98,7 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				Title:        t("TOOL_CREATE_GIST", "Create Gist"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"gist"}, UserOnly: true}),
 			mcp.WithString("description",
 				mcp.Description("Description of the gist"),
 			),
@@ -187,6 +189,7 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				Title:        t("TOOL_UPDATE_GIST", "Update Gist"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"gist"}, UserOnly: true}),
 			mcp.WithString("gist_id",
 				mcp.Required(),
 				mcp.Description("ID of the gist to update"),
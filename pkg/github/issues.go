@@ -110,9 +110,10 @@ type IssueFragment struct {
 	Author struct {
 		Login githubv4.String
 	}
-	CreatedAt githubv4.DateTime
-	UpdatedAt githubv4.DateTime
-	Labels    struct {
+	AuthorAssociation githubv4.String
+	CreatedAt         githubv4.DateTime
+	UpdatedAt         githubv4.DateTime
+	Labels            struct {
 		Nodes []struct {
 			Name        githubv4.String
 			ID          githubv4.String
@@ -217,11 +218,12 @@ func fragmentToIssue(fragment IssueFragment) *github.Issue {
 		User: &github.User{
 			Login: github.Ptr(string(fragment.Author.Login)),
 		},
-		State:    github.Ptr(string(fragment.State)),
-		ID:       github.Ptr(fragment.DatabaseID),
-		Body:     github.Ptr(string(fragment.Body)),
-		Labels:   foundLabels,
-		Comments: github.Ptr(int(fragment.Comments.TotalCount)),
+		AuthorAssociation: github.Ptr(string(fragment.AuthorAssociation)),
+		State:             github.Ptr(string(fragment.State)),
+		ID:                github.Ptr(fragment.DatabaseID),
+		Body:              github.Ptr(string(fragment.Body)),
+		Labels:            foundLabels,
+		Comments:          github.Ptr(int(fragment.Comments.TotalCount)),
 	}
 }
 
@@ -233,6 +235,7 @@ func GetIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				Title:        t("TOOL_GET_ISSUE_USER_TITLE", "Get issue details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository"),
@@ -278,6 +281,8 @@ func GetIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
 			}
 
+			LockdownFromContext(ctx).withhold(ctx, owner, repo, issue.User, issue.AuthorAssociation, &issue.Title, &issue.Body)
+
 			r, err := json.Marshal(issue)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal issue: %w", err)
@@ -296,6 +301,7 @@ func ListIssueTypes(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_ISSUE_TYPES_USER_TITLE", "List available issue types"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The organization owner of the repository"),
@@ -342,6 +348,7 @@ func AddIssueComment(getClient GetClientFn, t translations.TranslationHelperFunc
 				Title:        t("TOOL_ADD_ISSUE_COMMENT_USER_TITLE", "Add comment to issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -416,6 +423,7 @@ func AddSubIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				Title:        t("TOOL_ADD_SUB_ISSUE_USER_TITLE", "Add sub-issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -504,6 +512,7 @@ func ListSubIssues(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_SUB_ISSUES_USER_TITLE", "List sub-issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -598,6 +607,7 @@ func RemoveSubIssue(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_REMOVE_SUB_ISSUE_USER_TITLE", "Remove sub-issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -677,6 +687,7 @@ func ReprioritizeSubIssue(getClient GetClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_REPRIORITIZE_SUB_ISSUE_USER_TITLE", "Reprioritize sub-issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -790,6 +801,7 @@ func SearchIssues(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_SEARCH_ISSUES_USER_TITLE", "Search issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Search query using GitHub issues search syntax"),
@@ -835,6 +847,7 @@ func CreateIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				Title:        t("TOOL_CREATE_ISSUE_USER_TITLE", "Open new issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -976,6 +989,7 @@ func ListIssues(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_ISSUES_USER_TITLE", "List issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1154,8 +1168,11 @@ func ListIssues(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 
 			if queryResult, ok := issueQuery.(IssueQueryResult); ok {
 				fragment := queryResult.GetIssueFragment()
-				for _, issue := range fragment.Nodes {
-					issues = append(issues, fragmentToIssue(issue))
+				lockdown := LockdownFromContext(ctx)
+				for _, node := range fragment.Nodes {
+					issue := fragmentToIssue(node)
+					lockdown.withhold(ctx, owner, repo, issue.User, issue.AuthorAssociation, &issue.Title, &issue.Body)
+					issues = append(issues, issue)
 				}
 				pageInfo = fragment.PageInfo
 				totalCount = fragment.TotalCount
@@ -1188,6 +1205,7 @@ func UpdateIssue(getClient GetClientFn, getGQLClient GetGQLClientFn, t translati
 				Title:        t("TOOL_UPDATE_ISSUE_USER_TITLE", "Edit issue"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1445,6 +1463,7 @@ func GetIssueComments(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_GET_ISSUE_COMMENTS_USER_TITLE", "Get issue comments"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1502,6 +1521,11 @@ func GetIssueComments(getClient GetClientFn, t translations.TranslationHelperFun
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue comments: %s", string(body))), nil
 			}
 
+			lockdown := LockdownFromContext(ctx)
+			for _, comment := range comments {
+				lockdown.withhold(ctx, owner, repo, comment.User, comment.AuthorAssociation, &comment.Body)
+			}
+
 			r, err := json.Marshal(comments)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal response: %w", err)
@@ -1559,6 +1583,7 @@ func AssignCopilotToIssue(getGQLClient GetGQLClientFn, t translations.Translatio
 				ReadOnlyHint:   ToBoolPtr(false),
 				IdempotentHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
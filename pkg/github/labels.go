@@ -22,6 +22,7 @@ func GetLabel(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_GET_LABEL_TITLE", "Get a specific label from a repository."),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization name)"),
@@ -106,6 +107,7 @@ func ListLabels(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_LABEL_DESCRIPTION", "List labels from a repository."),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"issues:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization name) - required for all operations"),
@@ -248,6 +250,7 @@ func LabelWrite(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LABEL_WRITE_TITLE", "Write operations on repository labels."),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"issues:write"}}),
 			mcp.WithString("method",
 				mcp.Required(),
 				mcp.Description("Operation to perform: 'create', 'update', or 'delete'"),
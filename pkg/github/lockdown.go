@@ -0,0 +1,197 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithheldContent replaces the content lockdown mode withholds.
+const WithheldContent = "[Content withheld in lockdown mode: the author doesn't have push access to the repository]"
+
+const (
+	// maxLockdownSessions is the number of sessions whose permission lookups
+	// are remembered, the oldest session is forgotten first.
+	maxLockdownSessions = 1000
+	// lockdownPermissionTTL is how long a permission lookup is remembered, so
+	// that revoked access takes effect in long sessions, like those of the stdio
+	// server, which all share one session ID.
+	lockdownPermissionTTL = 10 * time.Minute
+)
+
+// Lockdown withholds content written by users without push access to the
+// repository, like the titles and bodies of issues and the bodies of comments,
+// so that anyone able to comment on a public repository can't slip instructions
+// to the agent. A nil *Lockdown withholds nothing.
+type Lockdown struct {
+	getClient    GetClientFn
+	trustedUsers map[string]bool
+	now          func() time.Time
+
+	// Permission lookups are remembered per session, as sessions may use
+	// different tokens, by "owner/repo/login"
+	mu          sync.Mutex
+	permissions map[string]map[string]lockdownPermission
+	sessions    []string
+}
+
+// lockdownPermission is a remembered permission lookup.
+type lockdownPermission struct {
+	trusted   bool
+	expiresAt time.Time
+}
+
+// NewLockdown returns a lockdown trusting the content of users with push access
+// to the repository it was written in, as looked up with getClient, and of the
+// trustedUsers regardless of the repository.
+func NewLockdown(getClient GetClientFn, trustedUsers []string) *Lockdown {
+	lockdown := &Lockdown{
+		getClient:    getClient,
+		trustedUsers: map[string]bool{},
+		now:          time.Now,
+		permissions:  map[string]map[string]lockdownPermission{},
+	}
+	for _, login := range trustedUsers {
+		if login = strings.ToLower(strings.TrimSpace(login)); login != "" {
+			lockdown.trustedUsers[login] = true
+		}
+	}
+	return lockdown
+}
+
+// ToolHandlerMiddleware makes the lockdown available to tools through
+// LockdownFromContext.
+func (l *Lockdown) ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return next(ContextWithLockdown(ctx, l), request)
+	}
+}
+
+type lockdownKey struct{}
+
+// ContextWithLockdown returns a context carrying the lockdown.
+func ContextWithLockdown(ctx context.Context, lockdown *Lockdown) context.Context {
+	return context.WithValue(ctx, lockdownKey{}, lockdown)
+}
+
+// LockdownFromContext returns the lockdown in ctx, or nil, which withholds
+// nothing, when there is none.
+func LockdownFromContext(ctx context.Context) *Lockdown {
+	lockdown, _ := ctx.Value(lockdownKey{}).(*Lockdown)
+	return lockdown
+}
+
+// withhold replaces the fields, like the title and body of an issue, with
+// WithheldContent when the lockdown doesn't trust author with content in
+// owner/repo. association is the author's association with the repository,
+// when known. Empty fields are left alone.
+func (l *Lockdown) withhold(ctx context.Context, owner, repo string, author *github.User, association *string, fields ...**string) {
+	if l == nil {
+		return
+	}
+	var withheld []**string
+	for _, field := range fields {
+		if *field != nil && **field != "" {
+			withheld = append(withheld, field)
+		}
+	}
+	if len(withheld) == 0 || l.trusts(ctx, owner, repo, author.GetLogin(), association) {
+		return
+	}
+	for _, field := range withheld {
+		*field = github.Ptr(WithheldContent)
+	}
+}
+
+// trusts reports whether the content of login in owner/repo can be shown.
+func (l *Lockdown) trusts(ctx context.Context, owner, repo, login string, association *string) bool {
+	login = strings.ToLower(login)
+	switch {
+	case login == "":
+		// Deleted users
+		return false
+	case l.trustedUsers[login]:
+		return true
+	case association == nil || *association == "":
+		return l.hasPushAccess(ctx, owner, repo, login)
+	}
+
+	switch strings.ToUpper(*association) {
+	case "OWNER":
+		return true
+	case "MEMBER", "COLLABORATOR":
+		return l.hasPushAccess(ctx, owner, repo, login)
+	default:
+		// Contributors, first-timers and others without an association with
+		// the repository can't push to it
+		return false
+	}
+}
+
+// hasPushAccess reports whether login can push to owner/repo, looking it up
+// once per session and lockdownPermissionTTL.
+func (l *Lockdown) hasPushAccess(ctx context.Context, owner, repo, login string) bool {
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	key := strings.ToLower(owner + "/" + repo + "/" + login)
+
+	l.mu.Lock()
+	permission, ok := l.permissions[sessionID][key]
+	l.mu.Unlock()
+	if ok && l.now().Before(permission.expiresAt) {
+		return permission.trusted
+	}
+
+	client, err := l.getClient(ctx)
+	if err != nil {
+		return false
+	}
+	level, resp, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, login)
+	if resp != nil {
+		defer func() { _ = resp.Body.Close() }()
+	}
+	if err != nil {
+		// The permission is hidden from tokens without push access themselves,
+		// which makes the author as untrusted as one without access. Other
+		// errors may be temporary, so they aren't remembered.
+		if resp == nil || (resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusNotFound) {
+			return false
+		}
+	}
+	trusted := err == nil && (level.GetPermission() == "admin" || level.GetPermission() == "write")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.permissions[sessionID]; !ok {
+		l.permissions[sessionID] = map[string]lockdownPermission{}
+		l.sessions = append(l.sessions, sessionID)
+		for len(l.sessions) > maxLockdownSessions {
+			delete(l.permissions, l.sessions[0])
+			l.sessions = l.sessions[1:]
+		}
+	}
+	l.permissions[sessionID][key] = lockdownPermission{trusted: trusted, expiresAt: l.now().Add(lockdownPermissionTTL)}
+	return trusted
+}
+
+// repositoryFromURL returns the owner and name of the repository of an API URL
+// such as https://api.github.com/repos/octocat/hello-world.
+func repositoryFromURL(url string) (string, string) {
+	_, path, ok := strings.Cut(url, "/repos/")
+	if !ok {
+		return "", ""
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
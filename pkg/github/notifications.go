@@ -30,6 +30,7 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				Title:        t("TOOL_LIST_NOTIFICATIONS_USER_TITLE", "List notifications"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("filter",
 				mcp.Description("Filter notifications to, use default unless specified. Read notifications are ones that have already been acknowledged by the user. Participating notifications are those that the user is directly involved in, such as issues or pull requests they have commented on or created."),
 				mcp.Enum(FilterDefault, FilterIncludeRead, FilterOnlyParticipating),
@@ -135,6 +136,17 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get notifications: %s", string(body))), nil
 			}
 
+			// Without a repository, notifications come from everywhere the user is subscribed
+			if policy := RepositoryPolicyFromContext(ctx); policy != nil {
+				allowed := make([]*github.Notification, 0, len(notifications))
+				for _, notification := range notifications {
+					if policy.checkNotification(notification) == nil {
+						allowed = append(allowed, notification)
+					}
+				}
+				notifications = allowed
+			}
+
 			// Marshal response to JSON
 			r, err := json.Marshal(notifications)
 			if err != nil {
@@ -153,6 +165,7 @@ func DismissNotification(getclient GetClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_DISMISS_NOTIFICATION_USER_TITLE", "Dismiss notification"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("threadID",
 				mcp.Required(),
 				mcp.Description("The ID of the notification thread"),
@@ -175,6 +188,10 @@ func DismissNotification(getclient GetClientFn, t translations.TranslationHelper
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			if result := checkNotificationThread(ctx, client, threadID); result != nil {
+				return result, nil
+			}
+
 			var resp *github.Response
 			switch state {
 			case "done":
@@ -217,9 +234,11 @@ func MarkAllNotificationsRead(getClient GetClientFn, t translations.TranslationH
 	return mcp.NewTool("mark_all_notifications_read",
 			mcp.WithDescription(t("TOOL_MARK_ALL_NOTIFICATIONS_READ_DESCRIPTION", "Mark all notifications as read")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_MARK_ALL_NOTIFICATIONS_READ_USER_TITLE", "Mark all notifications as read"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_MARK_ALL_NOTIFICATIONS_READ_USER_TITLE", "Mark all notifications as read"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("lastReadAt",
 				mcp.Description("Describes the last point that notifications were checked (optional). Default: Now"),
 			),
@@ -264,6 +283,10 @@ func MarkAllNotificationsRead(getClient GetClientFn, t translations.TranslationH
 				Time: lastReadTime,
 			}
 
+			if (owner == "" || repo == "") && RepositoryPolicyFromContext(ctx) != nil {
+				return mcp.NewToolResultError("owner and repo are required when access is restricted to specific repositories"), nil
+			}
+
 			var resp *github.Response
 			if owner != "" && repo != "" {
 				resp, err = client.Activity.MarkRepositoryNotificationsRead(ctx, owner, repo, markReadOptions)
@@ -299,6 +322,7 @@ func GetNotificationDetails(getClient GetClientFn, t translations.TranslationHel
 				Title:        t("TOOL_GET_NOTIFICATION_DETAILS_USER_TITLE", "Get notification details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("notificationID",
 				mcp.Required(),
 				mcp.Description("The ID of the notification"),
@@ -333,6 +357,12 @@ func GetNotificationDetails(getClient GetClientFn, t translations.TranslationHel
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get notification details: %s", string(body))), nil
 			}
 
+			if policy := RepositoryPolicyFromContext(ctx); policy != nil {
+				if err := policy.checkNotification(thread); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+
 			r, err := json.Marshal(thread)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal response: %w", err)
@@ -342,6 +372,30 @@ func GetNotificationDetails(getClient GetClientFn, t translations.TranslationHel
 		}
 }
 
+// checkNotificationThread returns an error result when the repository policy in
+// ctx doesn't allow the repository of the notification thread, and nil otherwise.
+func checkNotificationThread(ctx context.Context, client *github.Client, threadID string) *mcp.CallToolResult {
+	policy := RepositoryPolicyFromContext(ctx)
+	if policy == nil {
+		return nil
+	}
+
+	thread, resp, err := client.Activity.GetThread(ctx, threadID)
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx,
+			fmt.Sprintf("failed to get notification details for ID '%s'", threadID),
+			resp,
+			err,
+		)
+	}
+	_ = resp.Body.Close()
+
+	if err := policy.checkNotification(thread); err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	return nil
+}
+
 // Enum values for ManageNotificationSubscription action
 const (
 	NotificationActionIgnore = "ignore"
@@ -357,6 +411,7 @@ func ManageNotificationSubscription(getClient GetClientFn, t translations.Transl
 				Title:        t("TOOL_MANAGE_NOTIFICATION_SUBSCRIPTION_USER_TITLE", "Manage notification subscription"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("notificationID",
 				mcp.Required(),
 				mcp.Description("The ID of the notification thread."),
@@ -382,6 +437,10 @@ func ManageNotificationSubscription(getClient GetClientFn, t translations.Transl
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			if result := checkNotificationThread(ctx, client, notificationID); result != nil {
+				return result, nil
+			}
+
 			var (
 				resp   *github.Response
 				result any
@@ -442,6 +501,7 @@ func ManageRepositoryNotificationSubscription(getClient GetClientFn, t translati
 				Title:        t("TOOL_MANAGE_REPOSITORY_NOTIFICATION_SUBSCRIPTION_USER_TITLE", "Manage repository notification subscription"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"notifications", "repo"}, UserOnly: true}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The account owner of the repository."),
@@ -0,0 +1,334 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RepositoryPolicy restricts tools to a set of owners and repositories, whatever
+// else the token can access. A nil *RepositoryPolicy allows everything.
+type RepositoryPolicy struct {
+	scopes []repositoryScope
+
+	// userOwners caches whether owners are users rather than organizations,
+	// which decides how searches are limited to them
+	mu         sync.Mutex
+	userOwners map[string]bool
+}
+
+// repositoryScope is an allowed owner, or repository when repo is set. Both are
+// lower case globs, as GitHub names are case-insensitive.
+type repositoryScope struct {
+	owner string
+	repo  string
+}
+
+// wholeOwner reports whether the scope allows every repository of its owner.
+func (s repositoryScope) wholeOwner() bool {
+	return s.repo == "" || s.repo == "*"
+}
+
+// NewRepositoryPolicy returns a policy allowing the given owners, e.g. "octo-org",
+// and repositories, e.g. "octo-org/app". Both parts may be globs in the syntax of
+// path.Match, so "octo-org/svc-*" allows all repositories starting with "svc-".
+func NewRepositoryPolicy(scopes []string) (*RepositoryPolicy, error) {
+	policy := &RepositoryPolicy{userOwners: map[string]bool{}}
+	for _, scope := range scopes {
+		owner, repo, hasRepo := strings.Cut(strings.ToLower(strings.TrimSpace(scope)), "/")
+		if owner == "" || (hasRepo && (repo == "" || strings.Contains(repo, "/"))) {
+			return nil, fmt.Errorf("invalid repository scope %q, expected owner or owner/repo", scope)
+		}
+		for _, pattern := range []string{owner, repo} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid repository scope %q: %w", scope, err)
+			}
+		}
+		policy.scopes = append(policy.scopes, repositoryScope{owner: owner, repo: repo})
+	}
+	return policy, nil
+}
+
+// AllowsRepository reports whether the policy allows the repository owner/repo.
+func (p *RepositoryPolicy) AllowsRepository(owner, repo string) bool {
+	if p == nil {
+		return true
+	}
+	owner, repo = strings.ToLower(owner), strings.ToLower(repo)
+	for _, scope := range p.scopes {
+		if !globMatch(scope.owner, owner) {
+			continue
+		}
+		if scope.wholeOwner() || globMatch(scope.repo, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOwner reports whether the policy allows all of owner, which is required
+// for tools that act on an organization or user rather than a repository.
+func (p *RepositoryPolicy) AllowsOwner(owner string) bool {
+	if p == nil {
+		return true
+	}
+	owner = strings.ToLower(owner)
+	for _, scope := range p.scopes {
+		if scope.wholeOwner() && globMatch(scope.owner, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RepositoryPolicy) checkRepository(owner, repo string) error {
+	if !p.AllowsRepository(owner, repo) {
+		return fmt.Errorf("repository %s/%s is outside the repositories this server is allowed to access", owner, repo)
+	}
+	return nil
+}
+
+func (p *RepositoryPolicy) checkOwner(owner string) error {
+	if !p.AllowsOwner(owner) {
+		return fmt.Errorf("%s is outside the owners this server is allowed to access, specify a repository instead", owner)
+	}
+	return nil
+}
+
+func (p *RepositoryPolicy) checkNotification(notification *github.Notification) error {
+	repo := notification.GetRepository()
+	return p.checkRepository(repo.GetOwner().GetLogin(), repo.GetName())
+}
+
+// searchScopeQualifier matches the qualifiers that select the owners and
+// repositories a search covers.
+var searchScopeQualifier = regexp.MustCompile(`(^|[\s(])(-?)(repo|org|user|owner):"?([^\s")]+)`)
+
+// searchBooleanOperator matches the OR operator, which could widen a search
+// beyond the scope qualifiers.
+var searchBooleanOperator = regexp.MustCompile(`(^|[\s(])OR($|[\s)])`)
+
+// ScopeSearchQuery restricts a search query to the policy. Queries that name
+// owners or repositories must only name allowed ones; other queries are limited
+// to the allowed owners and repositories by adding qualifiers. Owners are looked
+// up with getClient to tell users from organizations.
+func (p *RepositoryPolicy) ScopeSearchQuery(ctx context.Context, getClient GetClientFn, query string) (string, error) {
+	if p == nil {
+		return query, nil
+	}
+	if searchBooleanOperator.MatchString(query) {
+		return "", fmt.Errorf("the OR operator is not supported when access is restricted to specific repositories")
+	}
+
+	scoped := false
+	for _, match := range searchScopeQualifier.FindAllStringSubmatch(query, -1) {
+		negated, qualifier, value := match[2] == "-", match[3], match[4]
+		if negated {
+			// Exclusions narrow a search, but don't scope it on their own
+			continue
+		}
+		scoped = true
+		if qualifier == "repo" {
+			owner, repo, ok := strings.Cut(value, "/")
+			if !ok {
+				return "", fmt.Errorf("invalid repo qualifier %q, expected repo:owner/name", value)
+			}
+			if err := p.checkRepository(owner, repo); err != nil {
+				return "", err
+			}
+		} else if err := p.checkOwner(value); err != nil {
+			return "", err
+		}
+	}
+	if scoped {
+		return query, nil
+	}
+
+	// Qualifiers can only be added for owners and repositories without globs
+	var qualifiers []string
+	for _, scope := range p.scopes {
+		switch {
+		case strings.ContainsAny(scope.owner, "*?[\\"):
+		case scope.wholeOwner():
+			isUser, err := p.isUser(ctx, getClient, scope.owner)
+			if err != nil {
+				return "", err
+			}
+			if isUser {
+				qualifiers = append(qualifiers, "user:"+scope.owner)
+			} else {
+				qualifiers = append(qualifiers, "org:"+scope.owner)
+			}
+		case !strings.ContainsAny(scope.repo, "*?[\\"):
+			qualifiers = append(qualifiers, "repo:"+scope.owner+"/"+scope.repo)
+		}
+	}
+	if len(qualifiers) == 0 {
+		return "", fmt.Errorf("add a repo:owner/name qualifier to the query to search one of the repositories this server is allowed to access")
+	}
+	return strings.Join(qualifiers, " ") + " " + query, nil
+}
+
+// isUser reports whether owner is a user rather than an organization, looking
+// it up on first use.
+func (p *RepositoryPolicy) isUser(ctx context.Context, getClient GetClientFn, owner string) (bool, error) {
+	p.mu.Lock()
+	isUser, ok := p.userOwners[owner]
+	p.mu.Unlock()
+	if ok {
+		return isUser, nil
+	}
+
+	client, err := getClient(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+	user, _, err := client.Users.Get(ctx, owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up allowed owner %s: %w", owner, err)
+	}
+	isUser = user.GetType() != "Organization"
+
+	p.mu.Lock()
+	p.userOwners[owner] = isUser
+	p.mu.Unlock()
+	return isUser, nil
+}
+
+// unscopedTools don't act on owners or repositories, or check the policy
+// themselves, see RepositoryPolicyFromContext.
+var unscopedTools = map[string]bool{
+	// Tools that only concern the server or the user
+	"get_me":                  true,
+	"enable_toolset":          true,
+	"disable_toolset":         true,
+	"find_tools":              true,
+	"list_available_toolsets": true,
+	"get_toolset_tools":       true,
+	// Public advisories in the GitHub Advisory Database
+	"get_global_security_advisory":    true,
+	"list_global_security_advisories": true,
+	// Tools checking the policy themselves
+	"search_code":                      true,
+	"search_repositories":              true,
+	"search_issues":                    true,
+	"search_pull_requests":             true,
+	"list_notifications":               true,
+	"get_notification_details":         true,
+	"dismiss_notification":             true,
+	"manage_notification_subscription": true,
+	"mark_all_notifications_read":      true,
+}
+
+// AllowsTool reports whether tool can be used under the policy at all, which is
+// the case for tools that take the owner of what they act on as an argument.
+// It can be used as a toolsets.ToolFilter.
+func (p *RepositoryPolicy) AllowsTool(tool mcp.Tool) bool {
+	if p == nil || unscopedTools[tool.Name] {
+		return true
+	}
+	for _, name := range []string{"owner", "org", "organization"} {
+		if _, ok := tool.InputSchema.Properties[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckToolCall returns an error when a tool call targets an owner or repository
+// the policy doesn't allow, or a tool that can't be restricted to repositories.
+func (p *RepositoryPolicy) CheckToolCall(request mcp.CallToolRequest) error {
+	if p == nil {
+		return nil
+	}
+	arguments := request.GetArguments()
+	stringArgument := func(name string) string {
+		value, _ := arguments[name].(string)
+		return value
+	}
+	owner, repo := stringArgument("owner"), stringArgument("repo")
+
+	// Tools creating repositories must create them in an allowed place
+	switch request.Params.Name {
+	case "create_repository":
+		if stringArgument("organization") == "" {
+			return fmt.Errorf("repositories can only be created in an organization this server is allowed to access")
+		}
+		return p.checkRepository(stringArgument("organization"), stringArgument("name"))
+	case "fork_repository":
+		if err := p.checkRepository(owner, repo); err != nil {
+			return err
+		}
+		if stringArgument("organization") == "" {
+			return fmt.Errorf("repositories can only be forked to an organization this server is allowed to access")
+		}
+		return p.checkRepository(stringArgument("organization"), repo)
+	}
+
+	switch {
+	case owner != "" && repo != "":
+		return p.checkRepository(owner, repo)
+	case owner != "":
+		return p.checkOwner(owner)
+	case stringArgument("org") != "":
+		return p.checkOwner(stringArgument("org"))
+	case stringArgument("organization") != "":
+		return p.checkOwner(stringArgument("organization"))
+	case unscopedTools[request.Params.Name]:
+		return nil
+	default:
+		return fmt.Errorf("%s is not available because access is restricted to specific repositories", request.Params.Name)
+	}
+}
+
+// ToolHandlerMiddleware rejects tool calls the policy doesn't allow, and makes
+// the policy available to tools through RepositoryPolicyFromContext.
+func (p *RepositoryPolicy) ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := p.CheckToolCall(request); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return next(ContextWithRepositoryPolicy(ctx, p), request)
+	}
+}
+
+// ResourceTemplateMiddleware rejects reads of repository resources the policy
+// doesn't allow.
+func (p *RepositoryPolicy) ResourceTemplateMiddleware(next server.ResourceTemplateHandlerFunc) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		owner, _ := request.Params.Arguments["owner"].([]string)
+		repo, _ := request.Params.Arguments["repo"].([]string)
+		if len(owner) > 0 && len(repo) > 0 {
+			if err := p.checkRepository(owner[0], repo[0]); err != nil {
+				return nil, err
+			}
+		}
+		return next(ContextWithRepositoryPolicy(ctx, p), request)
+	}
+}
+
+type repositoryPolicyKey struct{}
+
+// ContextWithRepositoryPolicy returns a context carrying the policy.
+func ContextWithRepositoryPolicy(ctx context.Context, policy *RepositoryPolicy) context.Context {
+	return context.WithValue(ctx, repositoryPolicyKey{}, policy)
+}
+
+// RepositoryPolicyFromContext returns the policy in ctx, or nil, which allows
+// everything, when there is none.
+func RepositoryPolicyFromContext(ctx context.Context) *RepositoryPolicy {
+	policy, _ := ctx.Value(repositoryPolicyKey{}).(*RepositoryPolicy)
+	return policy
+}
+
+func globMatch(pattern, name string) bool {
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
@@ -0,0 +1,261 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v74/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewRepositoryPolicy(t *testing.T) {
+	for _, scope := range []string{"", "/app", "octo-org/", "octo-org/app/extra", "octo-[org"} {
+		_, err := NewRepositoryPolicy([]string{scope})
+		assert.Error(t, err, "scope %q", scope)
+	}
+
+	policy, err := NewRepositoryPolicy([]string{"Octo-Org/App", "octo-org/svc-*", "octocat", "hubot/*"})
+	require.NoError(t, err)
+
+	assert.True(t, policy.AllowsRepository("octo-org", "app"))
+	assert.True(t, policy.AllowsRepository("OCTO-ORG", "APP"), "names are case-insensitive")
+	assert.True(t, policy.AllowsRepository("octo-org", "svc-billing"))
+	assert.False(t, policy.AllowsRepository("octo-org", "website"))
+	assert.True(t, policy.AllowsRepository("octocat", "hello-world"))
+	assert.True(t, policy.AllowsRepository("hubot", "scripts"))
+	assert.False(t, policy.AllowsRepository("other", "app"))
+
+	assert.False(t, policy.AllowsOwner("octo-org"), "only some repositories of octo-org are allowed")
+	assert.True(t, policy.AllowsOwner("octocat"))
+	assert.True(t, policy.AllowsOwner("hubot"))
+
+	var nilPolicy *RepositoryPolicy
+	assert.True(t, nilPolicy.AllowsRepository("other", "app"))
+	assert.True(t, nilPolicy.AllowsOwner("other"))
+}
+
+func Test_RepositoryPolicy_ScopeSearchQuery(t *testing.T) {
+	policy, err := NewRepositoryPolicy([]string{"octo-org/app", "octo-org/lib", "octocat", "octo-corp"})
+	require.NoError(t, err)
+
+	lookups := map[string]int{}
+	getClient := stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetUsersByUsername,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				login := path.Base(r.URL.Path)
+				lookups[login]++
+				accountType := "User"
+				if login == "octo-corp" {
+					accountType = "Organization"
+				}
+				_ = json.NewEncoder(w).Encode(&github.User{Login: github.Ptr(login), Type: github.Ptr(accountType)})
+			}),
+		),
+	)))
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedQuery  string
+		expectedErrMsg string
+	}{
+		{
+			name:          "qualifiers are added to unscoped queries",
+			query:         "is:open label:bug",
+			expectedQuery: "repo:octo-org/app repo:octo-org/lib user:octocat org:octo-corp is:open label:bug",
+		},
+		{
+			name:          "allowed repository",
+			query:         "is:issue repo:Octo-Org/App is:open",
+			expectedQuery: "is:issue repo:Octo-Org/App is:open",
+		},
+		{
+			name:          "allowed owner",
+			query:         "user:octocat language:go",
+			expectedQuery: "user:octocat language:go",
+		},
+		{
+			name:          "exclusions don't scope a query",
+			query:         "-repo:octo-org/lib fix",
+			expectedQuery: "repo:octo-org/app repo:octo-org/lib user:octocat org:octo-corp -repo:octo-org/lib fix",
+		},
+		{
+			name:           "repository outside the policy",
+			query:          "repo:octo-org/app repo:octo-org/website",
+			expectedErrMsg: "repository octo-org/website is outside the repositories this server is allowed to access",
+		},
+		{
+			name:           "owner of which only some repositories are allowed",
+			query:          "org:octo-org",
+			expectedErrMsg: "octo-org is outside the owners this server is allowed to access, specify a repository instead",
+		},
+		{
+			name:           "OR operator",
+			query:          "repo:octo-org/app bug OR (label:bug)",
+			expectedErrMsg: "the OR operator is not supported when access is restricted to specific repositories",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := policy.ScopeSearchQuery(context.Background(), getClient, tc.query)
+			if tc.expectedErrMsg != "" {
+				assert.EqualError(t, err, tc.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery, query)
+		})
+	}
+
+	assert.Equal(t, map[string]int{"octocat": 1, "octo-corp": 1}, lookups, "owners are looked up once")
+
+	t.Run("globs require qualifiers", func(t *testing.T) {
+		policy, err := NewRepositoryPolicy([]string{"octo-org/svc-*"})
+		require.NoError(t, err)
+		_, err = policy.ScopeSearchQuery(context.Background(), getClient, "is:open")
+		assert.ErrorContains(t, err, "add a repo:owner/name qualifier")
+	})
+}
+
+func Test_RepositoryPolicy_CheckToolCall(t *testing.T) {
+	policy, err := NewRepositoryPolicy([]string{"octo-org/app", "octocat"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		tool           string
+		args           map[string]any
+		expectedErrMsg string
+	}{
+		{name: "allowed repository", tool: "get_issue", args: map[string]any{"owner": "octo-org", "repo": "app", "issue_number": float64(1)}},
+		{name: "repository outside the policy", tool: "get_issue", args: map[string]any{"owner": "octo-org", "repo": "website"}, expectedErrMsg: "repository octo-org/website is outside the repositories this server is allowed to access"},
+		{name: "allowed owner", tool: "list_projects", args: map[string]any{"owner": "octocat", "owner_type": "user"}},
+		{name: "owner of an allowed repository", tool: "list_projects", args: map[string]any{"owner": "octo-org", "owner_type": "org"}, expectedErrMsg: "octo-org is outside the owners this server is allowed to access, specify a repository instead"},
+		{name: "organization", tool: "get_team_members", args: map[string]any{"org": "octo-org", "team_slug": "core"}, expectedErrMsg: "octo-org is outside the owners this server is allowed to access, specify a repository instead"},
+		{name: "tool without scope", tool: "list_gists", args: map[string]any{}, expectedErrMsg: "list_gists is not available because access is restricted to specific repositories"},
+		{name: "unscoped tool", tool: "get_me", args: map[string]any{}},
+		{name: "search", tool: "search_code", args: map[string]any{"query": "fmt.Println"}},
+		{name: "repository created in an allowed owner", tool: "create_repository", args: map[string]any{"name": "new", "organization": "octocat"}},
+		{name: "repository created in the user's account", tool: "create_repository", args: map[string]any{"name": "new"}, expectedErrMsg: "repositories can only be created in an organization this server is allowed to access"},
+		{name: "fork to an owner outside the policy", tool: "fork_repository", args: map[string]any{"owner": "octo-org", "repo": "app", "organization": "other"}, expectedErrMsg: "repository other/app is outside the repositories this server is allowed to access"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := createMCPRequest(tc.args)
+			request.Params.Name = tc.tool
+			err := policy.CheckToolCall(request)
+			if tc.expectedErrMsg != "" {
+				assert.EqualError(t, err, tc.expectedErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_RepositoryPolicy_AllowsTool(t *testing.T) {
+	policy, err := NewRepositoryPolicy([]string{"octo-org/app"})
+	require.NoError(t, err)
+
+	getIssue, _ := GetIssue(nil, translations.NullTranslationHelper)
+	searchCode, _ := SearchCode(nil, translations.NullTranslationHelper)
+	listGists, _ := ListGists(nil, translations.NullTranslationHelper)
+
+	assert.True(t, policy.AllowsTool(getIssue))
+	assert.True(t, policy.AllowsTool(searchCode))
+	assert.False(t, policy.AllowsTool(listGists))
+}
+
+func Test_RepositoryPolicy_Tools(t *testing.T) {
+	policy, err := NewRepositoryPolicy([]string{"octo-org/app"})
+	require.NoError(t, err)
+	ctx := ContextWithRepositoryPolicy(context.Background(), policy)
+
+	t.Run("search queries are scoped", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetSearchIssues,
+				expectQueryParams(t, map[string]string{
+					"q":        "repo:octo-org/app is:issue is:open",
+					"page":     "1",
+					"per_page": "30",
+				}).andThen(
+					mockResponse(t, http.StatusOK, &github.IssuesSearchResult{}),
+				),
+			),
+		)
+		_, handler := SearchIssues(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		result, err := handler(ctx, createMCPRequest(map[string]any{"query": "is:open"}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		result, err = handler(ctx, createMCPRequest(map[string]any{"query": "repo:octo-org/website is:open"}))
+		require.NoError(t, err)
+		assert.Equal(t, "repository octo-org/website is outside the repositories this server is allowed to access", getErrorResult(t, result).Text)
+	})
+
+	notification := func(id, owner, repo string) *github.Notification {
+		return &github.Notification{
+			ID: github.Ptr(id),
+			Repository: &github.Repository{
+				Name:  github.Ptr(repo),
+				Owner: &github.User{Login: github.Ptr(owner)},
+			},
+		}
+	}
+
+	t.Run("notifications are filtered", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetNotifications,
+				[]*github.Notification{notification("1", "octo-org", "app"), notification("2", "octo-org", "website")},
+			),
+		)
+		_, handler := ListNotifications(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		result, err := handler(ctx, createMCPRequest(map[string]any{}))
+		require.NoError(t, err)
+		var notifications []*github.Notification
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &notifications))
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "1", notifications[0].GetID())
+	})
+
+	t.Run("notification threads outside the policy are not changed", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetNotificationsThreadsByThreadId,
+				notification("2", "octo-org", "website"),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchNotificationsThreadsByThreadId,
+				http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+					t.Error("the thread must not be marked as read")
+				}),
+			),
+		)
+		_, handler := DismissNotification(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		result, err := handler(ctx, createMCPRequest(map[string]any{"threadID": "2", "state": "read"}))
+		require.NoError(t, err)
+		assert.Equal(t, "repository octo-org/website is outside the repositories this server is allowed to access", getErrorResult(t, result).Text)
+	})
+
+	t.Run("all notifications can't be marked as read", func(t *testing.T) {
+		_, handler := MarkAllNotificationsRead(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+
+		result, err := handler(ctx, createMCPRequest(map[string]any{}))
+		require.NoError(t, err)
+		assert.Equal(t, "owner and repo are required when access is restricted to specific repositories", getErrorResult(t, result).Text)
+	})
+}
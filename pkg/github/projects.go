@@ -32,6 +32,7 @@ func ListProjects(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_LIST_PROJECTS_USER_TITLE", "List projects"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(), mcp.Description("Owner type"), mcp.Enum("user", "org"),
 			),
@@ -128,6 +129,7 @@ func GetProject(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				Title:        t("TOOL_GET_PROJECT_USER_TITLE", "Get project"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithNumber("project_number",
 				mcp.Required(),
 				mcp.Description("The project's number"),
@@ -212,6 +214,7 @@ func ListProjectFields(getClient GetClientFn, t translations.TranslationHelperFu
 				Title:        t("TOOL_LIST_PROJECT_FIELDS_USER_TITLE", "List project fields"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"),
@@ -301,6 +304,7 @@ func GetProjectField(getClient GetClientFn, t translations.TranslationHelperFunc
 				Title:        t("TOOL_GET_PROJECT_FIELD_USER_TITLE", "Get project field"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"), mcp.Enum("user", "org")),
@@ -384,6 +388,7 @@ func ListProjectItems(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_PROJECT_ITEMS_USER_TITLE", "List project items"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"),
@@ -487,6 +492,7 @@ func GetProjectItem(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_GET_PROJECT_ITEM_USER_TITLE", "Get project item"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"read:project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"),
@@ -573,6 +579,7 @@ func AddProjectItem(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_ADD_PROJECT_ITEM_USER_TITLE", "Add project item"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"), mcp.Enum("user", "org"),
@@ -675,6 +682,7 @@ func UpdateProjectItem(getClient GetClientFn, t translations.TranslationHelperFu
 				Title:        t("TOOL_UPDATE_PROJECT_ITEM_USER_TITLE", "Update project item"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(), mcp.Description("Owner type"),
 				mcp.Enum("user", "org"),
@@ -780,6 +788,7 @@ func DeleteProjectItem(getClient GetClientFn, t translations.TranslationHelperFu
 				Title:        t("TOOL_DELETE_PROJECT_ITEM_USER_TITLE", "Delete project item"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"project"}}),
 			mcp.WithString("owner_type",
 				mcp.Required(),
 				mcp.Description("Owner type"),
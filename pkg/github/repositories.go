@@ -25,6 +25,7 @@ func GetCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				Title:        t("TOOL_GET_COMMITS_USER_TITLE", "Get commit details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -112,6 +113,7 @@ func ListCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				Title:        t("TOOL_LIST_COMMITS_USER_TITLE", "List commits"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -208,6 +210,7 @@ func ListBranches(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_LIST_BRANCHES_USER_TITLE", "List branches"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -285,6 +288,7 @@ func CreateOrUpdateFile(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_CREATE_OR_UPDATE_FILE_USER_TITLE", "Create or update file"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"contents:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization)"),
@@ -398,6 +402,7 @@ func CreateRepository(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_CREATE_REPOSITORY_USER_TITLE", "Create repository"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"administration:write"}}),
 			mcp.WithString("name",
 				mcp.Required(),
 				mcp.Description("Repository name"),
@@ -489,6 +494,7 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 				Title:        t("TOOL_GET_FILE_CONTENTS_USER_TITLE", "Get file or directory contents"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization)"),
@@ -694,6 +700,7 @@ func ForkRepository(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_FORK_REPOSITORY_USER_TITLE", "Fork repository"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"administration:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -781,6 +788,7 @@ func DeleteFile(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				ReadOnlyHint:    ToBoolPtr(false),
 				DestructiveHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"contents:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization)"),
@@ -951,6 +959,7 @@ func CreateBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_CREATE_BRANCH_USER_TITLE", "Create branch"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"contents:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1052,6 +1061,7 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				Title:        t("TOOL_PUSH_FILES_USER_TITLE", "Push files to repository"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, Permissions: []string{"contents:write"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1224,6 +1234,7 @@ func ListTags(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				Title:        t("TOOL_LIST_TAGS_USER_TITLE", "List tags"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1293,6 +1304,7 @@ func GetTag(getClient GetClientFn, t translations.TranslationHelperFunc) (tool m
 				Title:        t("TOOL_GET_TAG_USER_TITLE", "Get tag details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1380,6 +1392,7 @@ func ListReleases(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_LIST_RELEASES_USER_TITLE", "List releases"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1445,6 +1458,7 @@ func GetLatestRelease(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_GET_LATEST_RELEASE_USER_TITLE", "Get latest release"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1499,6 +1513,7 @@ func GetReleaseByTag(getClient GetClientFn, t translations.TranslationHelperFunc
 				Title:        t("TOOL_GET_RELEASE_BY_TAG_USER_TITLE", "Get a release by tag name"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"contents:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1701,6 +1716,7 @@ func ListStarredRepositories(getClient GetClientFn, t translations.TranslationHe
 				Title:        t("TOOL_LIST_STARRED_REPOSITORIES_USER_TITLE", "List starred repositories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{UserOnly: true}),
 			mcp.WithString("username",
 				mcp.Description("Username to list starred repositories for. Defaults to the authenticated user."),
 			),
@@ -1821,6 +1837,7 @@ func StarRepository(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_STAR_REPOSITORY_USER_TITLE", "Star repository"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, UserOnly: true}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1875,6 +1892,7 @@ func UnstarRepository(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_UNSTAR_REPOSITORY_USER_TITLE", "Unstar repository"),
 				ReadOnlyHint: ToBoolPtr(false),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: repoWriteScopes, UserOnly: true}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TokenRequirements describes what a tool needs from the token it is called with.
+type TokenRequirements struct {
+	// Scopes lists the classic OAuth scopes that grant access to the tool. Holding
+	// any one of them, directly or through a parent scope, is sufficient.
+	Scopes []string `json:"scopes,omitempty"`
+	// Permissions lists the fine-grained permissions, as "resource:level", that an
+	// installation token must hold. All of them are required.
+	Permissions []string `json:"permissions,omitempty"`
+	// UserOnly marks tools that act on the authenticated user and so cannot be
+	// used with GitHub App installation tokens.
+	UserOnly bool `json:"user_only,omitempty"`
+}
+
+var (
+	repoWriteScopes     = []string{"repo", "public_repo"}
+	securityEventScopes = []string{"security_events", "public_repo"}
+)
+
+// tokenRequirementsMetaKey is the key under which tools publish their token
+// requirements in their _meta.
+const tokenRequirementsMetaKey = "github/token_requirements"
+
+// WithTokenRequirements declares what a tool needs from the token it is called
+// with. Every tool must declare its requirements, empty ones if it works with
+// any token, usually because it searches or reads public data.
+func WithTokenRequirements(requirements TokenRequirements) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		if tool.Meta == nil {
+			tool.Meta = &mcp.Meta{}
+		}
+		if tool.Meta.AdditionalFields == nil {
+			tool.Meta.AdditionalFields = map[string]any{}
+		}
+		tool.Meta.AdditionalFields[tokenRequirementsMetaKey] = requirements
+	}
+}
+
+// impliedScopes lists the scopes that are granted implicitly by a parent scope.
+// See https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/scopes-for-oauth-apps#available-scopes
+var impliedScopes = map[string][]string{
+	"repo":             {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":        {"write:org", "read:org"},
+	"write:org":        {"read:org"},
+	"admin:public_key": {"write:public_key", "read:public_key"},
+	"write:public_key": {"read:public_key"},
+	"admin:repo_hook":  {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook":  {"read:repo_hook"},
+	"admin:gpg_key":    {"write:gpg_key", "read:gpg_key"},
+	"write:gpg_key":    {"read:gpg_key"},
+	"user":             {"read:user", "user:email", "user:follow"},
+	"project":          {"read:project"},
+	"write:packages":   {"read:packages"},
+	"write:discussion": {"read:discussion"},
+}
+
+// permissionLevels orders fine-grained permission levels so that a higher level
+// satisfies a requirement for a lower one.
+var permissionLevels = map[string]int{
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+// ToolTokenRequirements returns the token requirements tool declares with
+// WithTokenRequirements, and whether it declares any.
+func ToolTokenRequirements(tool mcp.Tool) (TokenRequirements, bool) {
+	if tool.Meta == nil {
+		return TokenRequirements{}, false
+	}
+	requirements, ok := tool.Meta.AdditionalFields[tokenRequirementsMetaKey].(TokenRequirements)
+	return requirements, ok
+}
+
+// TokenGrants describes what a token is allowed to do, as far as that can be
+// determined up front.
+type TokenGrants struct {
+	// Scopes granted to a classic token or OAuth token, nil if unknown
+	Scopes []string
+	// Permissions granted to a GitHub App installation token, keyed by resource, nil if unknown
+	Permissions map[string]string
+}
+
+// ParseOAuthScopes parses the X-OAuth-Scopes response header. It returns nil when
+// the header is absent, as it is for fine-grained tokens whose access can't be
+// determined from it.
+func ParseOAuthScopes(header []string) []string {
+	if header == nil {
+		return nil
+	}
+	scopes := []string{}
+	for _, value := range header {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Known reports whether the grants carry enough information to judge tools by.
+func (g TokenGrants) Known() bool {
+	return g.Scopes != nil || g.Permissions != nil
+}
+
+// Allows reports whether a token with these grants can use tool. When nothing
+// is known about the token, every tool is allowed.
+func (g TokenGrants) Allows(tool mcp.Tool) bool {
+	requirements, _ := ToolTokenRequirements(tool)
+
+	if g.Permissions != nil {
+		if requirements.UserOnly {
+			return false
+		}
+		for _, required := range requirements.Permissions {
+			resource, level, _ := strings.Cut(required, ":")
+			if permissionLevels[g.Permissions[resource]] < permissionLevels[level] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if g.Scopes != nil && len(requirements.Scopes) > 0 {
+		granted := expandScopes(g.Scopes)
+		for _, scope := range requirements.Scopes {
+			if granted[scope] {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func expandScopes(scopes []string) map[string]bool {
+	expanded := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		expanded[scope] = true
+		for _, implied := range impliedScopes[scope] {
+			expanded[implied] = true
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOAuthScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		expected []string
+	}{
+		{
+			name:     "header absent",
+			header:   nil,
+			expected: nil,
+		},
+		{
+			name:     "no scopes",
+			header:   []string{""},
+			expected: []string{},
+		},
+		{
+			name:     "comma separated scopes",
+			header:   []string{"repo, read:org,gist"},
+			expected: []string{"repo", "read:org", "gist"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseOAuthScopes(tc.header))
+		})
+	}
+}
+
+func TestTokenGrants_Allows(t *testing.T) {
+	tests := []struct {
+		name     string
+		grants   TokenGrants
+		tool     string
+		expected bool
+	}{
+		{
+			name:     "unknown grants allow everything",
+			grants:   TokenGrants{},
+			tool:     "run_workflow",
+			expected: true,
+		},
+		{
+			name:     "tools without requirements are always allowed",
+			grants:   TokenGrants{Scopes: []string{}},
+			tool:     "get_issue",
+			expected: true,
+		},
+		{
+			name:     "missing scope",
+			grants:   TokenGrants{Scopes: []string{"public_repo"}},
+			tool:     "run_workflow",
+			expected: false,
+		},
+		{
+			name:     "granted scope",
+			grants:   TokenGrants{Scopes: []string{"repo"}},
+			tool:     "run_workflow",
+			expected: true,
+		},
+		{
+			name:     "scope implied by parent scope",
+			grants:   TokenGrants{Scopes: []string{"repo"}},
+			tool:     "list_code_scanning_alerts",
+			expected: true,
+		},
+		{
+			name:     "any of the scopes is sufficient",
+			grants:   TokenGrants{Scopes: []string{"security_events"}},
+			tool:     "list_secret_scanning_alerts",
+			expected: true,
+		},
+		{
+			name:     "read scope does not grant writes",
+			grants:   TokenGrants{Scopes: []string{"read:project"}},
+			tool:     "update_project_item",
+			expected: false,
+		},
+		{
+			name:     "missing permission",
+			grants:   TokenGrants{Permissions: map[string]string{"metadata": "read"}},
+			tool:     "list_secret_scanning_alerts",
+			expected: false,
+		},
+		{
+			name:     "insufficient permission level",
+			grants:   TokenGrants{Permissions: map[string]string{"actions": "read"}},
+			tool:     "run_workflow",
+			expected: false,
+		},
+		{
+			name:     "higher permission level satisfies lower one",
+			grants:   TokenGrants{Permissions: map[string]string{"actions": "write"}},
+			tool:     "list_workflows",
+			expected: true,
+		},
+		{
+			name:     "all permissions are required",
+			grants:   TokenGrants{Permissions: map[string]string{"pull_requests": "write"}},
+			tool:     "merge_pull_request",
+			expected: false,
+		},
+		{
+			name:     "user tools are unavailable to installations",
+			grants:   TokenGrants{Permissions: map[string]string{}},
+			tool:     "list_notifications",
+			expected: false,
+		},
+	}
+
+	tools := defaultTools()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Contains(t, tools, tc.tool)
+			assert.Equal(t, tc.expected, tc.grants.Allows(tools[tc.tool]))
+		})
+	}
+}
+
+func TestToolTokenRequirements_DeclaredByAllTools(t *testing.T) {
+	for name, tool := range defaultTools() {
+		requirements, ok := ToolTokenRequirements(tool)
+		if !assert.True(t, ok, "tool %s declares no token requirements, add WithTokenRequirements, empty if it works with any token", name) {
+			continue
+		}
+		for _, permission := range requirements.Permissions {
+			_, level, ok := strings.Cut(permission, ":")
+			require.True(t, ok, "permission %s of tool %s must be resource:level", permission, name)
+			require.Contains(t, permissionLevels, level, "permission %s of tool %s has an unknown level", permission, name)
+		}
+	}
+}
+
+// defaultTools returns the tools of all default toolsets by name.
+func defaultTools() map[string]mcp.Tool {
+	tsg := DefaultToolsetGroup(false, stubGetClientFn(nil), stubGetGQLClientFn(nil), stubGetRawClientFn(nil), nil, translations.NullTranslationHelper, 5000)
+
+	tools := map[string]mcp.Tool{}
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			tools[tool.Tool.Name] = tool.Tool
+		}
+	}
+	return tools
+}
@@ -22,6 +22,7 @@ func SearchRepositories(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_SEARCH_REPOSITORIES_USER_TITLE", "Search repositories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Repository search query. Examples: 'machine learning in:name stars:>1000 language:python', 'topic:react', 'user:facebook'. Supports advanced search syntax for precise filtering."),
@@ -45,6 +46,10 @@ func SearchRepositories(getClient GetClientFn, t translations.TranslationHelperF
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			query, err = RepositoryPolicyFromContext(ctx).ScopeSearchQuery(ctx, getClient, query)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 			sort, err := OptionalParam[string](request, "sort")
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -155,6 +160,7 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				Title:        t("TOOL_SEARCH_CODE_USER_TITLE", "Search code"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Search query using GitHub's powerful code search syntax. Examples: 'content:Skill language:Java org:github', 'NOT is:archived language:Python OR language:go', 'repo:github/github-mcp-server'. Supports exact matching, language filters, path filters, and more."),
@@ -173,6 +179,10 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			query, err = RepositoryPolicyFromContext(ctx).ScopeSearchQuery(ctx, getClient, query)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 			sort, err := OptionalParam[string](request, "sort")
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -323,6 +333,7 @@ func SearchUsers(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			Title:        t("TOOL_SEARCH_USERS_USER_TITLE", "Search users"),
 			ReadOnlyHint: ToBoolPtr(true),
 		}),
+		WithTokenRequirements(TokenRequirements{}),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("User search query. Examples: 'john smith', 'location:seattle', 'followers:>100'. Search is automatically scoped to type:user."),
@@ -348,6 +359,7 @@ func SearchOrgs(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 			Title:        t("TOOL_SEARCH_ORGS_USER_TITLE", "Search organizations"),
 			ReadOnlyHint: ToBoolPtr(true),
 		}),
+		WithTokenRequirements(TokenRequirements{}),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Organization search query. Examples: 'microsoft', 'location:california', 'created:>=2025-01-01'. Search is automatically scoped to type:org."),
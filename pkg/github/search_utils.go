@@ -65,6 +65,11 @@ func searchHandler(
 		query = fmt.Sprintf("repo:%s/%s %s", owner, repo, query)
 	}
 
+	query, err = RepositoryPolicyFromContext(ctx).ScopeSearchQuery(ctx, getClient, query)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	sort, err := OptionalParam[string](request, "sort")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -106,6 +111,12 @@ func searchHandler(
 		return mcp.NewToolResultError(fmt.Sprintf("%s: %s", errorPrefix, string(body))), nil
 	}
 
+	lockdown := LockdownFromContext(ctx)
+	for _, issue := range result.Issues {
+		issueOwner, issueRepo := repositoryFromURL(issue.GetRepositoryURL())
+		lockdown.withhold(ctx, issueOwner, issueRepo, issue.User, issue.AuthorAssociation, &issue.Title, &issue.Body)
+	}
+
 	r, err := json.Marshal(result)
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to marshal response: %w", errorPrefix, err)
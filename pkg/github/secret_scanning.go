@@ -22,6 +22,7 @@ func GetSecretScanningAlert(getClient GetClientFn, t translations.TranslationHel
 				Title:        t("TOOL_GET_SECRET_SCANNING_ALERT_USER_TITLE", "Get secret scanning alert"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"security_events", "repo"}, Permissions: []string{"secret_scanning_alerts:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -89,6 +90,7 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 				Title:        t("TOOL_LIST_SECRET_SCANNING_ALERTS_USER_TITLE", "List secret scanning alerts"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Scopes: []string{"security_events", "repo"}, Permissions: []string{"secret_scanning_alerts:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -20,6 +20,7 @@ func ListGlobalSecurityAdvisories(getClient GetClientFn, t translations.Translat
 				Title:        t("TOOL_LIST_GLOBAL_SECURITY_ADVISORIES_USER_TITLE", "List global security advisories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("ghsaId",
 				mcp.Description("Filter by GitHub Security Advisory ID (format: GHSA-xxxx-xxxx-xxxx)."),
 			),
@@ -189,6 +190,7 @@ func ListRepositorySecurityAdvisories(getClient GetClientFn, t translations.Tran
 				Title:        t("TOOL_LIST_REPOSITORY_SECURITY_ADVISORIES_USER_TITLE", "List repository security advisories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"repository_advisories:read"}}),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -278,6 +280,7 @@ func GetGlobalSecurityAdvisory(getClient GetClientFn, t translations.Translation
 				Title:        t("TOOL_GET_GLOBAL_SECURITY_ADVISORY_USER_TITLE", "Get a global security advisory"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{}),
 			mcp.WithString("ghsaId",
 				mcp.Description("GitHub Security Advisory ID (format: GHSA-xxxx-xxxx-xxxx)."),
 				mcp.Required(),
@@ -323,6 +326,7 @@ func ListOrgRepositorySecurityAdvisories(getClient GetClientFn, t translations.T
 				Title:        t("TOOL_LIST_ORG_REPOSITORY_SECURITY_ADVISORIES_USER_TITLE", "List org repository security advisories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			WithTokenRequirements(TokenRequirements{Permissions: []string{"repository_advisories:read"}}),
 			mcp.WithString("org",
 				mcp.Required(),
 				mcp.Description("The organization login."),
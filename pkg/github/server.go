@@ -189,6 +189,16 @@ func OptionalStringArrayParam(r mcp.CallToolRequest, p string) ([]string, error)
 	}
 }
 
+// WithoutParameters declares that a tool takes no parameters. Its input schema
+// is given raw because mcp-go drops an empty "properties" object when it
+// marshals a structured one, which some clients reject.
+func WithoutParameters() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		tool.InputSchema = mcp.ToolInputSchema{}
+		tool.RawInputSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+}
+
 // WithPagination adds REST API pagination parameters to a tool.
 // https://docs.github.com/en/rest/using-the-rest-api/using-pagination-in-the-rest-api
 func WithPagination() mcp.ToolOption {
@@ -1,17 +1,31 @@
 package log
 
 import (
+	"bytes"
 	"io"
+	"sync"
 
 	"log/slog"
 )
 
+// maxPendingLine bounds the data buffered while waiting for the end of a line
+// when redacting. Longer lines are logged in parts.
+const maxPendingLine = 1 << 20
+
+// pendingOverlap is the end of a long line that is kept back when logging a
+// part of it, so that a secret cut off by the part is found whole in the next.
+const pendingOverlap = 8 << 10
+
 // IOLogger is a wrapper around io.Reader and io.Writer that can be used
 // to log the data being read and written from the underlying streams
 type IOLogger struct {
 	reader io.Reader
 	writer io.Writer
 	logger *slog.Logger
+
+	redactor *Redactor
+	stdin    lineLogger
+	stdout   lineLogger
 }
 
 // NewIOLogger creates a new IOLogger instance
@@ -23,12 +37,26 @@ func NewIOLogger(r io.Reader, w io.Writer, logger *slog.Logger) *IOLogger {
 	}
 }
 
+// WithRedactor makes the logger redact secrets from the logged data. Data is
+// then logged a line, i.e. a JSON-RPC message, at a time rather than as it is
+// read, so that secrets split across reads are still found.
+func (l *IOLogger) WithRedactor(redactor *Redactor) *IOLogger {
+	l.redactor = redactor
+	return l
+}
+
 // Read reads data from the underlying io.Reader and logs it.
 func (l *IOLogger) Read(p []byte) (n int, err error) {
 	if l.reader == nil {
 		return 0, io.EOF
 	}
 	n, err = l.reader.Read(p)
+	if l.redactor != nil {
+		l.stdin.log(p[:n], err != nil, l.redactor, func(line []byte) {
+			l.logger.Info("[stdin]: received bytes", "count", len(line), "data", l.redactor.Redact(string(line)))
+		})
+		return n, err
+	}
 	if n > 0 {
 		l.logger.Info("[stdin]: received bytes", "count", n, "data", string(p[:n]))
 	}
@@ -40,6 +68,52 @@ func (l *IOLogger) Write(p []byte) (n int, err error) {
 	if l.writer == nil {
 		return 0, io.ErrClosedPipe
 	}
+	if l.redactor != nil {
+		l.stdout.log(p, false, l.redactor, func(line []byte) {
+			l.logger.Info("[stdout]: sending bytes", "count", len(line), "data", l.redactor.Redact(string(line)))
+		})
+		return l.writer.Write(p)
+	}
 	l.logger.Info("[stdout]: sending bytes", "count", len(p), "data", string(p))
 	return l.writer.Write(p)
 }
+
+// lineLogger assembles data into lines before logging it.
+type lineLogger struct {
+	mu      sync.Mutex
+	pending []byte
+}
+
+// log buffers data and calls logLine for every complete line, including its
+// newline. When flush is set, the remaining data is logged as well. Parts of
+// long lines are cut where redactor finds no secret.
+func (ll *lineLogger) log(data []byte, flush bool, redactor *Redactor, logLine func(line []byte)) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	ll.pending = append(ll.pending, data...)
+	for {
+		i := bytes.IndexByte(ll.pending, '\n')
+		if i < 0 {
+			break
+		}
+		logLine(ll.pending[:i+1])
+		ll.pending = ll.pending[i+1:]
+	}
+	if len(ll.pending) > 0 && flush {
+		logLine(ll.pending)
+		ll.pending = nil
+	} else if len(ll.pending) >= maxPendingLine {
+		cut := redactor.splitPoint(ll.pending, len(ll.pending)-pendingOverlap)
+		if cut == 0 {
+			// A single secret fills the buffer
+			cut = len(ll.pending)
+		}
+		logLine(ll.pending[:cut])
+		ll.pending = append([]byte(nil), ll.pending[cut:]...)
+	}
+	if len(ll.pending) == 0 {
+		// Release the memory of long lines
+		ll.pending = nil
+	}
+}
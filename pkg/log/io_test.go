@@ -2,8 +2,10 @@ package log
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"log/slog"
 
@@ -63,3 +65,59 @@ func removeTimeAttr(groups []string, a slog.Attr) slog.Attr {
 	}
 	return a
 }
+
+func TestLoggedReadWriterWithRedactor(t *testing.T) {
+	redactor, err := NewRedactor(nil)
+	assert.NoError(t, err)
+	token := "ghp_" + strings.Repeat("a", 36)
+
+	t.Run("secrets split across reads are redacted", func(t *testing.T) {
+		input := `{"token":"` + token + `"}` + "\n" + `{"partial":true}`
+		var logBuffer bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+		lrw := NewIOLogger(iotest.OneByteReader(strings.NewReader(input)), nil, logger).WithRedactor(redactor)
+
+		data, err := io.ReadAll(lrw)
+		assert.NoError(t, err)
+		assert.Equal(t, input, string(data))
+
+		output := logBuffer.String()
+		assert.NotContains(t, output, token)
+		assert.Contains(t, output, `[REDACTED:github_token]`)
+		assert.Equal(t, 2, strings.Count(output, "[stdin]"), "each line must be logged once")
+		assert.Contains(t, output, `{\"partial\":true}`, "data without a newline must be logged at EOF")
+	})
+
+	t.Run("secrets in long lines are redacted", func(t *testing.T) {
+		// Place the token across the end of the buffer and across the part of
+		// the line that is logged first.
+		for _, offset := range []int{maxPendingLine - 10, maxPendingLine - pendingOverlap - 10} {
+			line := strings.Repeat(" ", offset) + token + strings.Repeat(" ", pendingOverlap) + "\n"
+			var writeBuffer, logBuffer bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+			lrw := NewIOLogger(nil, &writeBuffer, logger).WithRedactor(redactor)
+
+			_, err := lrw.Write([]byte(line[:maxPendingLine]))
+			assert.NoError(t, err)
+			_, err = lrw.Write([]byte(line[maxPendingLine:]))
+			assert.NoError(t, err)
+			assert.True(t, writeBuffer.String() == line)
+			assert.False(t, strings.Contains(logBuffer.String(), token[:20]), "no part of the token may be logged")
+			assert.True(t, strings.Contains(logBuffer.String(), "[REDACTED:github_token]"))
+			assert.Equal(t, 2, strings.Count(logBuffer.String(), "[stdout]"), "long lines must be logged in parts")
+		}
+	})
+
+	t.Run("written data is redacted", func(t *testing.T) {
+		var writeBuffer, logBuffer bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+		lrw := NewIOLogger(nil, &writeBuffer, logger).WithRedactor(redactor)
+
+		n, err := lrw.Write([]byte(token + "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, len(token)+1, n)
+		assert.Equal(t, token+"\n", writeBuffer.String())
+		assert.NotContains(t, logBuffer.String(), token)
+		assert.Contains(t, logBuffer.String(), "[stdout]")
+	})
+}
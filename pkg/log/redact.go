@@ -0,0 +1,146 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+)
+
+// detector finds one kind of secret.
+type detector struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// builtinDetectors find credentials that commonly appear in file contents,
+// workflow logs and secret scanning alerts.
+var builtinDetectors = []detector{
+	// https://github.blog/engineering/platform-security/behind-githubs-new-authentication-token-formats/
+	{"github_token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,251}\b`)},
+	{"github_token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,247}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	// Keys are matched up to the END line, or to the end of the text when it
+	// has been cut off. Newlines may be escaped, as they are in JSON.
+	{"private_key", regexp.MustCompile(`-----BEGIN[A-Z0-9 ]*PRIVATE KEY-----(?:[\s\S]*?-----END[A-Z0-9 ]*PRIVATE KEY-----|[A-Za-z0-9+/=\s\\]*)`)},
+	{"aws_access_key_id", regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_access_key", regexp.MustCompile(`(?i)aws_?secret_?access_?key\\?["']?\s*[:=]\s*\\?["']?[A-Za-z0-9/+=]{40}`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"azure_storage_key", regexp.MustCompile(`(?i)AccountKey=[A-Za-z0-9+/]{86}==`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"stripe_key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+}
+
+// Redactor replaces secrets in text with a marker naming the kind of secret,
+// e.g. "[REDACTED:github_token]". It is safe for concurrent use.
+type Redactor struct {
+	detectors []detector
+}
+
+// NewRedactor returns a redactor using the built-in detectors and the given
+// additional regular expressions, whose matches are replaced with "[REDACTED]".
+func NewRedactor(patterns []string) (*Redactor, error) {
+	detectors := append([]detector(nil), builtinDetectors...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		detectors = append(detectors, detector{pattern: re})
+	}
+	return &Redactor{detectors: detectors}, nil
+}
+
+// Redact returns s with all secrets replaced. A nil *Redactor returns s unchanged.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, d := range r.detectors {
+		marker := "[REDACTED]"
+		if d.name != "" {
+			marker = "[REDACTED:" + d.name + "]"
+		}
+		s = d.pattern.ReplaceAllLiteralString(s, marker)
+	}
+	return s
+}
+
+// splitPoint returns the last index not after n at which data can be cut
+// without splitting a secret, so that both parts can be redacted on their own.
+func (r *Redactor) splitPoint(data []byte, n int) int {
+	if r == nil {
+		return n
+	}
+	for moved := true; moved; {
+		moved = false
+		for _, d := range r.detectors {
+			for _, match := range d.pattern.FindAllIndex(data, -1) {
+				if match[0] < n && n < match[1] {
+					n = match[0]
+					moved = true
+				}
+			}
+		}
+	}
+	return n
+}
+
+// RedactingHandler is a slog.Handler that redacts secrets from the message and
+// attributes of records before passing them on to another handler.
+type RedactingHandler struct {
+	handler  slog.Handler
+	redactor *Redactor
+}
+
+// NewRedactingHandler wraps handler so that secrets found by redactor never reach it.
+func NewRedactingHandler(handler slog.Handler, redactor *Redactor) *RedactingHandler {
+	return &RedactingHandler{handler: handler, redactor: redactor}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactAttr(attr)
+	}
+	return &RedactingHandler{handler: h.handler.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{handler: h.handler.WithGroup(name), redactor: h.redactor}
+}
+
+func (h *RedactingHandler) redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.redactor.Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		// Values such as errors are logged as text, so check that text
+		text := fmt.Sprint(value.Any())
+		if redacted := h.redactor.Redact(text); redacted != text {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,279 @@
+// Package sanitize removes content that people reading GitHub don't see, but
+// that agents reading the markdown source do, such as HTML comments and
+// invisible characters, from user-authored text.
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rules of the sanitizer, each removing one kind of hidden content.
+const (
+	// InvisibleCharacters removes bidirectional controls, zero-width spaces,
+	// word joiners, byte order marks and Unicode tag characters. Zero-width
+	// joiners and non-joiners and soft hyphens are kept, as emoji sequences
+	// and many scripts depend on them.
+	InvisibleCharacters = "invisible-characters"
+	// HTMLComments removes HTML comments, which GitHub doesn't render.
+	HTMLComments = "html-comments"
+	// ImageAltText removes the alt text of images, which is only shown when
+	// the image can't be.
+	ImageAltText = "image-alt"
+	// Details replaces collapsed <details> sections with a note naming their
+	// summary, as their content is only shown when expanded.
+	Details = "details"
+)
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)`)
+	// Unterminated sections are collapsed to the end of the text
+	detailsPattern          = regexp.MustCompile(`(?is)<details\b[^>]*>(.*?)(?:</details\s*>|$)`)
+	summaryPattern          = regexp.MustCompile(`(?is)<summary\b[^>]*>(.*?)</summary\s*>`)
+	markdownImageAltPattern = regexp.MustCompile(`!\[([^\]]+)\]([(\[])`)
+	htmlImageAltPattern     = regexp.MustCompile(`(?i)(<img\b[^>]*?)\s+alt\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+)`)
+	codePlaceholderPattern  = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// Sanitizer removes hidden content from text. A nil *Sanitizer leaves text unchanged.
+type Sanitizer struct {
+	rules map[string]bool
+}
+
+// NewSanitizer returns a sanitizer applying the given rules, or all rules for
+// "all". It returns nil for no rules or "none".
+func NewSanitizer(rules []string) (*Sanitizer, error) {
+	s := &Sanitizer{rules: map[string]bool{}}
+	for _, rule := range rules {
+		switch rule = strings.TrimSpace(rule); rule {
+		case "", "none":
+		case "all":
+			for _, rule := range []string{InvisibleCharacters, HTMLComments, ImageAltText, Details} {
+				s.rules[rule] = true
+			}
+		case InvisibleCharacters, HTMLComments, ImageAltText, Details:
+			s.rules[rule] = true
+		default:
+			return nil, fmt.Errorf("unknown sanitization rule %q, expected %s, %s, %s, %s, all or none", rule, InvisibleCharacters, HTMLComments, ImageAltText, Details)
+		}
+	}
+	if len(s.rules) == 0 {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// Changes counts the changes made by each rule.
+type Changes map[string]int
+
+// Add adds the counts of other to c.
+func (c Changes) Add(other Changes) {
+	for rule, count := range other {
+		c[rule] += count
+	}
+}
+
+// Sanitize returns text without the hidden content, and the changes made.
+func (s *Sanitizer) Sanitize(text string) (string, Changes) {
+	changes := Changes{}
+	if s == nil {
+		return text, changes
+	}
+
+	// Invisible characters go first, so that they can't break up the markup
+	// the other rules look for
+	if s.rules[InvisibleCharacters] {
+		removed := 0
+		text = strings.Map(func(r rune) rune {
+			if isInvisible(r) {
+				removed++
+				return -1
+			}
+			return r
+		}, text)
+		if removed > 0 {
+			changes[InvisibleCharacters] = removed
+		}
+	}
+
+	// Code is shown as it is, so the markup rules leave it alone
+	text, unmaskCode := maskCode(text)
+	if s.rules[HTMLComments] {
+		text = replaceCounting(htmlCommentPattern, text, changes, HTMLComments, func([]string) string { return "" })
+	}
+	if s.rules[Details] {
+		text = replaceCounting(detailsPattern, text, changes, Details, func(match []string) string {
+			summary := summaryPattern.FindStringSubmatch(match[1])
+			if summary == nil || strings.TrimSpace(summary[1]) == "" {
+				return "[collapsed section removed]"
+			}
+			return fmt.Sprintf("[collapsed section removed: %s]", strings.TrimSpace(summary[1]))
+		})
+	}
+	if s.rules[ImageAltText] {
+		text = replaceCounting(markdownImageAltPattern, text, changes, ImageAltText, func(match []string) string {
+			return "![]" + match[2]
+		})
+		text = replaceCounting(htmlImageAltPattern, text, changes, ImageAltText, func(match []string) string {
+			return match[1]
+		})
+	}
+	return unmaskCode(text), changes
+}
+
+// isInvisible reports whether r is a character that isn't rendered and that
+// text has no need for: bidirectional controls, which can make text read
+// differently from how it's stored, zero-width spaces, word joiners and
+// invisible operators, byte order marks and tag characters.
+func isInvisible(r rune) bool {
+	switch {
+	case r == 0x061C, r == 0x200E, r == 0x200F: // bidirectional marks
+	case r >= 0x202A && r <= 0x202E, r >= 0x2066 && r <= 0x2069: // bidirectional embeddings and isolates
+	case r == 0x200B: // zero-width space
+	case r >= 0x2060 && r <= 0x2064: // word joiner and invisible operators
+	case r == 0xFEFF: // byte order mark
+	case r >= 0xE0000 && r <= 0xE007F: // tags
+	default:
+		return false
+	}
+	return true
+}
+
+// maskCode replaces the fenced code blocks and code spans of text with
+// placeholders, and returns a function putting them back. Text containing NUL
+// characters, which markdown doesn't allow, is left as it is.
+func maskCode(text string) (string, func(string) string) {
+	if strings.Contains(text, "\x00") {
+		return text, func(text string) string { return text }
+	}
+
+	var code []string
+	var masked, prose strings.Builder
+	placeholder := func(s string) string {
+		code = append(code, s)
+		return "\x00" + strconv.Itoa(len(code)-1) + "\x00"
+	}
+	flushProse := func() {
+		masked.WriteString(maskCodeSpans(prose.String(), placeholder))
+		prose.Reset()
+	}
+
+	var fence, block string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if fence != "" {
+			block += line
+			if isClosingFence(line, fence) {
+				masked.WriteString(placeholder(block))
+				fence, block = "", ""
+			}
+			continue
+		}
+		if fence = openingFence(line); fence != "" {
+			flushProse()
+			block = line
+			continue
+		}
+		prose.WriteString(line)
+	}
+	// Unterminated blocks run to the end of the text
+	if block != "" {
+		masked.WriteString(placeholder(block))
+	}
+	flushProse()
+
+	return masked.String(), func(text string) string {
+		return codePlaceholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			i, err := strconv.Atoi(match[1 : len(match)-1])
+			if err != nil || i >= len(code) {
+				return match
+			}
+			return code[i]
+		})
+	}
+}
+
+// openingFence returns the fence opening a code block on line, or "" if the
+// line doesn't open one.
+func openingFence(line string) string {
+	line, ok := trimFenceIndent(line)
+	if !ok || (!strings.HasPrefix(line, "```") && !strings.HasPrefix(line, "~~~")) {
+		return ""
+	}
+	fence := line[:len(line)-len(strings.TrimLeft(line, line[:1]))]
+	// The info string of a backtick fence can't contain backticks
+	if fence[0] == '`' && strings.Contains(line[len(fence):], "`") {
+		return ""
+	}
+	return fence
+}
+
+// isClosingFence reports whether line closes the code block opened by fence.
+func isClosingFence(line, fence string) bool {
+	line, ok := trimFenceIndent(line)
+	if !ok || !strings.HasPrefix(line, fence) {
+		return false
+	}
+	return strings.TrimSpace(strings.TrimLeft(line, fence[:1])) == ""
+}
+
+// trimFenceIndent removes the up to three spaces a fence can be indented by.
+func trimFenceIndent(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	return trimmed, len(line)-len(trimmed) <= 3
+}
+
+// maskCodeSpans replaces the code spans of text, delimited by backtick strings
+// of the same length, using placeholder.
+func maskCodeSpans(text string, placeholder func(string) string) string {
+	var masked strings.Builder
+	for {
+		start := strings.IndexByte(text, '`')
+		if start < 0 {
+			masked.WriteString(text)
+			return masked.String()
+		}
+		n := len(text[start:]) - len(strings.TrimLeft(text[start:], "`"))
+		// Escaped backticks don't open a code span
+		escaped := start > 0 && text[start-1] == '\\'
+		end := -1
+		if !escaped {
+			end = closingBackticks(text[start+n:], n)
+		}
+		if end < 0 {
+			masked.WriteString(text[:start+n])
+			text = text[start+n:]
+			continue
+		}
+		masked.WriteString(text[:start])
+		masked.WriteString(placeholder(text[start : start+n+end+n]))
+		text = text[start+n+end+n:]
+	}
+}
+
+// closingBackticks returns the index in text of the first string of exactly n
+// backticks, or -1 if there is none.
+func closingBackticks(text string, n int) int {
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// replaceCounting replaces the matches of pattern in text using replace, which
+// gets the submatches, and counts them as changes of rule.
+func replaceCounting(pattern *regexp.Regexp, text string, changes Changes, rule string, replace func(match []string) string) string {
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		changes[rule]++
+		return replace(pattern.FindStringSubmatch(match))
+	})
+}
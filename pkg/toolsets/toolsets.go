@@ -2,6 +2,7 @@ package toolsets
 
 import (
 	"fmt"
+	"path"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -47,6 +48,45 @@ func NewServerPrompt(prompt mcp.Prompt, handler server.PromptHandlerFunc) server
 	}
 }
 
+// ToolFilter decides whether a tool should be offered. Tools for which any filter
+// returns false are left out of the active and available tools of a toolset.
+type ToolFilter func(tool mcp.Tool) bool
+
+// NewToolNameFilter returns a filter offering the tools whose names match one of
+// the include patterns, or any tool when there are none, unless they match one of
+// the exclude patterns. Patterns are globs in the syntax of path.Match, e.g. "delete_*".
+func NewToolNameFilter(include, exclude []string) (ToolFilter, error) {
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return func(tool mcp.Tool) bool {
+		if len(include) > 0 && !MatchesAnyPattern(include, tool.Name) {
+			return false
+		}
+		return !MatchesAnyPattern(exclude, tool.Name)
+	}, nil
+}
+
+// MatchesAnyPattern reports whether name matches one of the glob patterns.
+// Invalid patterns match nothing.
+func MatchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolDecorator changes a tool together with its handler, e.g. to add an
+// argument the decorated handler takes care of.
+type ToolDecorator func(tool server.ServerTool) server.ServerTool
+
+// ResourceTemplateMiddleware wraps the handler of a resource template.
+type ResourceTemplateMiddleware func(server.ResourceTemplateHandlerFunc) server.ResourceTemplateHandlerFunc
+
 // Toolset represents a collection of MCP functionality that can be enabled or disabled as a group.
 type Toolset struct {
 	Name        string
@@ -60,37 +100,92 @@ type Toolset struct {
 	resourceTemplates []server.ServerResourceTemplate
 	// prompts are also not tools but are namespaced similarly
 	prompts []server.ServerPrompt
+	filters []ToolFilter
+	// writeDecorators change the write tools
+	writeDecorators []ToolDecorator
+	// resourceMiddlewares wrap the handlers of the resource templates
+	resourceMiddlewares []ResourceTemplateMiddleware
 }
 
 func (t *Toolset) GetActiveTools() []server.ServerTool {
 	if t.Enabled {
-		if t.readOnly {
-			return t.readTools
-		}
-		return append(t.readTools, t.writeTools...)
+		return t.GetAvailableTools()
 	}
 	return nil
 }
 
 func (t *Toolset) GetAvailableTools() []server.ServerTool {
-	if t.readOnly {
-		return t.readTools
+	tools := make([]server.ServerTool, 0, len(t.readTools)+len(t.writeTools))
+	tools = append(tools, t.readTools...)
+	if !t.readOnly {
+		for _, tool := range t.writeTools {
+			tools = append(tools, t.wrapWriteTool(tool))
+		}
 	}
-	return append(t.readTools, t.writeTools...)
+	return t.filterTools(tools)
 }
 
-func (t *Toolset) RegisterTools(s *server.MCPServer) {
-	if !t.Enabled {
-		return
+// AddWriteToolMiddleware wraps the handlers of the write tools of the toolset
+// with middleware, e.g. to audit or confirm changes made on the user's behalf.
+// Middleware added first runs first.
+func (t *Toolset) AddWriteToolMiddleware(middleware server.ToolHandlerMiddleware) *Toolset {
+	return t.AddWriteToolDecorator(middlewareDecorator(middleware))
+}
+
+// AddWriteToolDecorator changes the write tools of the toolset with decorator.
+// The handlers of decorators added first run first.
+func (t *Toolset) AddWriteToolDecorator(decorator ToolDecorator) *Toolset {
+	t.writeDecorators = append(t.writeDecorators, decorator)
+	return t
+}
+
+func (t *Toolset) wrapWriteTool(tool server.ServerTool) server.ServerTool {
+	for i := len(t.writeDecorators) - 1; i >= 0; i-- {
+		tool = t.writeDecorators[i](tool)
+	}
+	return tool
+}
+
+// middlewareDecorator returns a decorator wrapping the handler of tools with middleware.
+func middlewareDecorator(middleware server.ToolHandlerMiddleware) ToolDecorator {
+	return func(tool server.ServerTool) server.ServerTool {
+		tool.Handler = middleware(tool.Handler)
+		return tool
 	}
-	for _, tool := range t.readTools {
+}
+
+func (t *Toolset) RegisterTools(s *server.MCPServer) {
+	for _, tool := range t.GetActiveTools() {
 		s.AddTool(tool.Tool, tool.Handler)
 	}
-	if !t.readOnly {
-		for _, tool := range t.writeTools {
-			s.AddTool(tool.Tool, tool.Handler)
+}
+
+// AddToolFilter hides the tools of the toolset for which filter returns false.
+func (t *Toolset) AddToolFilter(filter ToolFilter) *Toolset {
+	t.filters = append(t.filters, filter)
+	return t
+}
+
+func (t *Toolset) filterTools(tools []server.ServerTool) []server.ServerTool {
+	if len(t.filters) == 0 {
+		return tools
+	}
+	result := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		if t.allows(tool.Tool) {
+			result = append(result, tool)
 		}
 	}
+	return result
+}
+
+func (t *Toolset) allows(tool mcp.Tool) bool {
+	for _, filter := range t.filters {
+		if !filter(tool) {
+			return false
+		}
+	}
+	return true
 }
 
 func (t *Toolset) AddResourceTemplates(templates ...server.ServerResourceTemplate) *Toolset {
@@ -107,18 +202,32 @@ func (t *Toolset) GetActiveResourceTemplates() []server.ServerResourceTemplate {
 	if !t.Enabled {
 		return nil
 	}
-	return t.resourceTemplates
+	return t.GetAvailableResourceTemplates()
 }
 
 func (t *Toolset) GetAvailableResourceTemplates() []server.ServerResourceTemplate {
-	return t.resourceTemplates
+	if len(t.resourceMiddlewares) == 0 {
+		return t.resourceTemplates
+	}
+	templates := make([]server.ServerResourceTemplate, len(t.resourceTemplates))
+	for i, template := range t.resourceTemplates {
+		for j := len(t.resourceMiddlewares) - 1; j >= 0; j-- {
+			template.Handler = t.resourceMiddlewares[j](template.Handler)
+		}
+		templates[i] = template
+	}
+	return templates
+}
+
+// AddResourceTemplateMiddleware wraps the handlers of the resource templates of
+// the toolset with middleware. Middleware added first runs first.
+func (t *Toolset) AddResourceTemplateMiddleware(middleware ResourceTemplateMiddleware) *Toolset {
+	t.resourceMiddlewares = append(t.resourceMiddlewares, middleware)
+	return t
 }
 
 func (t *Toolset) RegisterResourcesTemplates(s *server.MCPServer) {
-	if !t.Enabled {
-		return
-	}
-	for _, resource := range t.resourceTemplates {
+	for _, resource := range t.GetActiveResourceTemplates() {
 		s.AddResourceTemplate(resource.Template, resource.Handler)
 	}
 }
@@ -164,6 +273,11 @@ type ToolsetGroup struct {
 	Toolsets     map[string]*Toolset
 	everythingOn bool
 	readOnly     bool
+	filters      []ToolFilter
+	// writeDecorators change the write tools of every toolset
+	writeDecorators []ToolDecorator
+	// resourceMiddlewares wrap the handlers of the resource templates of every toolset
+	resourceMiddlewares []ResourceTemplateMiddleware
 }
 
 func NewToolsetGroup(readOnly bool) *ToolsetGroup {
@@ -178,9 +292,51 @@ func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	if tg.readOnly {
 		ts.SetReadOnly()
 	}
+	for _, filter := range tg.filters {
+		ts.AddToolFilter(filter)
+	}
+	for _, decorator := range tg.writeDecorators {
+		ts.AddWriteToolDecorator(decorator)
+	}
+	for _, middleware := range tg.resourceMiddlewares {
+		ts.AddResourceTemplateMiddleware(middleware)
+	}
 	tg.Toolsets[ts.Name] = ts
 }
 
+// AddToolFilter hides the tools for which filter returns false from every toolset
+// in the group, including toolsets added later.
+func (tg *ToolsetGroup) AddToolFilter(filter ToolFilter) {
+	tg.filters = append(tg.filters, filter)
+	for _, toolset := range tg.Toolsets {
+		toolset.AddToolFilter(filter)
+	}
+}
+
+// AddWriteToolMiddleware wraps the handlers of the write tools of every toolset
+// in the group, including toolsets added later.
+func (tg *ToolsetGroup) AddWriteToolMiddleware(middleware server.ToolHandlerMiddleware) {
+	tg.AddWriteToolDecorator(middlewareDecorator(middleware))
+}
+
+// AddWriteToolDecorator changes the write tools of every toolset in the group,
+// including toolsets added later.
+func (tg *ToolsetGroup) AddWriteToolDecorator(decorator ToolDecorator) {
+	tg.writeDecorators = append(tg.writeDecorators, decorator)
+	for _, toolset := range tg.Toolsets {
+		toolset.AddWriteToolDecorator(decorator)
+	}
+}
+
+// AddResourceTemplateMiddleware wraps the handlers of the resource templates of
+// every toolset in the group, including toolsets added later.
+func (tg *ToolsetGroup) AddResourceTemplateMiddleware(middleware ResourceTemplateMiddleware) {
+	tg.resourceMiddlewares = append(tg.resourceMiddlewares, middleware)
+	for _, toolset := range tg.Toolsets {
+		toolset.AddResourceTemplateMiddleware(middleware)
+	}
+}
+
 func NewToolset(name string, description string) *Toolset {
 	return &Toolset{
 		Name:        name,
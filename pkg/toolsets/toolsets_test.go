@@ -1,10 +1,27 @@
 package toolsets
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
+func newTestTool(name string, readOnly bool) mcp.Tool {
+	return mcp.NewTool(name, mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly}))
+}
+
+func toolNames(tools []server.ServerTool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	return names
+}
+
 func TestNewToolsetGroupIsEmptyWithoutEverythingOn(t *testing.T) {
 	tsg := NewToolsetGroup(false)
 	if len(tsg.Toolsets) != 0 {
@@ -262,3 +279,171 @@ func TestToolsetGroup_GetToolset(t *testing.T) {
 		t.Errorf("expected error to be ToolsetDoesNotExistError, got %v", err)
 	}
 }
+
+func TestToolsetGroup_AddToolFilter(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+	toolset := NewToolset("my-toolset", "desc").
+		AddReadTools(
+			NewServerTool(newTestTool("get_thing", true), nil),
+			NewServerTool(newTestTool("list_things", true), nil),
+		).
+		AddWriteTools(
+			NewServerTool(newTestTool("delete_thing", false), nil),
+		)
+	tsg.AddToolset(toolset)
+
+	tsg.AddToolFilter(func(tool mcp.Tool) bool {
+		return tool.Name != "delete_thing"
+	})
+
+	// Filters also apply to toolsets added after the filter
+	later := NewToolset("later-toolset", "desc").
+		AddReadTools(NewServerTool(newTestTool("delete_other", true), nil))
+	tsg.AddToolset(later)
+	tsg.AddToolFilter(func(tool mcp.Tool) bool {
+		return tool.Name != "delete_other"
+	})
+
+	got := toolNames(toolset.GetAvailableTools())
+	if len(got) != 2 || got[0] != "get_thing" || got[1] != "list_things" {
+		t.Errorf("expected filtered tools [get_thing list_things], got %v", got)
+	}
+
+	if len(toolset.GetActiveTools()) != 0 {
+		t.Error("expected no active tools for a disabled toolset")
+	}
+	toolset.Enabled = true
+	if got := toolNames(toolset.GetActiveTools()); len(got) != 2 {
+		t.Errorf("expected 2 active tools, got %v", got)
+	}
+
+	if got := later.GetAvailableTools(); len(got) != 0 {
+		t.Errorf("expected later toolset to be filtered, got %v", toolNames(got))
+	}
+}
+
+func TestToolsetGroup_AddWriteToolMiddleware(t *testing.T) {
+	var calls []string
+	handler := func(name string) server.ToolHandlerFunc {
+		return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls = append(calls, name)
+			return mcp.NewToolResultText(name), nil
+		}
+	}
+	middleware := func(name string) server.ToolHandlerMiddleware {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, name)
+				return next(ctx, request)
+			}
+		}
+	}
+
+	tsg := NewToolsetGroup(false)
+	tsg.AddWriteToolMiddleware(middleware("first"))
+	toolset := NewToolset("my-toolset", "desc").
+		AddReadTools(NewServerTool(newTestTool("get_thing", true), handler("get_thing"))).
+		AddWriteTools(NewServerTool(newTestTool("delete_thing", false), handler("delete_thing")))
+	tsg.AddToolset(toolset)
+	tsg.AddWriteToolMiddleware(middleware("second"))
+
+	for _, tool := range toolset.GetAvailableTools() {
+		if _, err := tool.Handler(context.Background(), mcp.CallToolRequest{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{"get_thing", "first", "second", "delete_thing"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("expected calls %v, got %v", expected, calls)
+		}
+	}
+}
+
+func TestToolsetGroup_AddWriteToolDecorator(t *testing.T) {
+	decorator := func(tool server.ServerTool) server.ServerTool {
+		tool.Tool.Description += " (decorated)"
+		return tool
+	}
+
+	tsg := NewToolsetGroup(false)
+	tsg.AddWriteToolDecorator(decorator)
+	toolset := NewToolset("my-toolset", "desc").
+		AddReadTools(NewServerTool(newTestTool("get_thing", true), nil)).
+		AddWriteTools(NewServerTool(newTestTool("delete_thing", false), nil))
+	tsg.AddToolset(toolset)
+
+	for _, tool := range toolset.GetAvailableTools() {
+		decorated := strings.HasSuffix(tool.Tool.Description, " (decorated)")
+		if decorated != (tool.Tool.Name == "delete_thing") {
+			t.Errorf("tool %s: decorated = %v", tool.Tool.Name, decorated)
+		}
+	}
+}
+
+func TestNewToolNameFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			name:     "no patterns",
+			expected: []string{"get_thing", "list_things", "delete_thing", "merge_thing"},
+		},
+		{
+			name:     "exclude",
+			exclude:  []string{"delete_*", "merge_thing"},
+			expected: []string{"get_thing", "list_things"},
+		},
+		{
+			name:     "include",
+			include:  []string{"get_*", "merge_thing"},
+			expected: []string{"get_thing", "merge_thing"},
+		},
+		{
+			name:     "exclude takes precedence",
+			include:  []string{"*_thing"},
+			exclude:  []string{"merge_*"},
+			expected: []string{"get_thing", "delete_thing"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := NewToolNameFilter(tc.include, tc.exclude)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			toolset := NewToolset("my-toolset", "desc").
+				AddReadTools(
+					NewServerTool(newTestTool("get_thing", true), nil),
+					NewServerTool(newTestTool("list_things", true), nil),
+				).
+				AddWriteTools(
+					NewServerTool(newTestTool("delete_thing", false), nil),
+					NewServerTool(newTestTool("merge_thing", false), nil),
+				).
+				AddToolFilter(filter)
+
+			got := toolNames(toolset.GetAvailableTools())
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected tools %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("expected tools %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+
+	if _, err := NewToolNameFilter(nil, []string{"delete_["}); err == nil {
+		t.Error("expected error for invalid pattern, got nil")
+	}
+}
@@ -17,7 +17,18 @@ func NullTranslationHelper(_ string, defaultValue string) string {
 }
 
 func TranslationHelper() (TranslationHelperFunc, func()) {
+	return TranslationHelperWithOverrides(nil)
+}
+
+// TranslationHelperWithOverrides is like TranslationHelper, but the given overrides,
+// e.g. from the server configuration file, take precedence over the ones in
+// github-mcp-server-config.json. Environment variables still take precedence over both.
+func TranslationHelperWithOverrides(overrides map[string]string) (TranslationHelperFunc, func()) {
 	var translationKeyMap = map[string]string{}
+	var overrideMap = make(map[string]string, len(overrides))
+	for key, value := range overrides {
+		overrideMap[strings.ToUpper(key)] = value
+	}
 	v := viper.New()
 
 	// Load from JSON file
@@ -44,6 +55,10 @@ func TranslationHelper() (TranslationHelperFunc, func()) {
 				translationKeyMap[key] = value
 				return value
 			}
+			if value, exists := overrideMap[key]; exists {
+				translationKeyMap[key] = value
+				return value
+			}
 
 			v.SetDefault(key, defaultValue)
 			translationKeyMap[key] = v.GetString(key)